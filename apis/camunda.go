@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	ccv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	ccv1beta1 "github.com/salaboy/provider-camunda-cloud/apis/cc/v1beta1"
 	camundav1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
 )
 
@@ -29,6 +30,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes,
 		camundav1alpha1.SchemeBuilder.AddToScheme,
 		ccv1alpha1.SchemeBuilder.AddToScheme,
+		ccv1beta1.SchemeBuilder.AddToScheme,
 	)
 }
 
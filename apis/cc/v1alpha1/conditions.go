@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Condition types.
+const (
+	// TypeThrottled indicates whether the Camunda Cloud Console API is
+	// throttling the requests made to reconcile a resource.
+	TypeThrottled xpv1.ConditionType = "Throttled"
+
+	// TypeZeebeReady indicates whether the Zeebe brokers and gateway of a
+	// cluster are healthy.
+	TypeZeebeReady xpv1.ConditionType = "ZeebeReady"
+
+	// TypeOperateReady indicates whether Operate is healthy.
+	TypeOperateReady xpv1.ConditionType = "OperateReady"
+
+	// TypeTasklistReady indicates whether Tasklist is healthy.
+	TypeTasklistReady xpv1.ConditionType = "TasklistReady"
+
+	// TypeOptimizeReady indicates whether Optimize is healthy.
+	TypeOptimizeReady xpv1.ConditionType = "OptimizeReady"
+
+	// TypeDeletionBlocked indicates whether the provider refuses to delete
+	// a cluster.
+	TypeDeletionBlocked xpv1.ConditionType = "DeletionBlocked"
+
+	// TypeChangesDue indicates whether changes of a cluster are due that must
+	// be made in the Camunda Console.
+	TypeChangesDue xpv1.ConditionType = "ChangesDue"
+)
+
+// Condition reasons.
+const (
+	ReasonRateLimited  xpv1.ConditionReason = "RateLimited"
+	ReasonNotThrottled xpv1.ConditionReason = "NotThrottled"
+
+	ReasonComponentHealthy     xpv1.ConditionReason = "Healthy"
+	ReasonComponentCreating    xpv1.ConditionReason = "Creating"
+	ReasonComponentUnhealthy   xpv1.ConditionReason = "Unhealthy"
+	ReasonComponentNotReported xpv1.ConditionReason = "NotReported"
+
+	ReasonDeletionProtection xpv1.ConditionReason = "DeletionProtection"
+	ReasonDeletionAllowed    xpv1.ConditionReason = "DeletionAllowed"
+
+	ReasonChangesDue   xpv1.ConditionReason = "ChangesDue"
+	ReasonNoChangesDue xpv1.ConditionReason = "NoChangesDue"
+)
+
+// Component statuses reported by the Console API.
+const (
+	ComponentStatusHealthy  = "Healthy"
+	ComponentStatusCreating = "Creating"
+)
+
+// Throttled returns a condition indicating that the Console API is throttling
+// requests. Unlike a ReconcileError this indicates a quota problem rather than
+// a failure. Its message does not say when requests are retried, so that it
+// does not change while they remain throttled.
+func Throttled() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeThrottled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRateLimited,
+		Message:            "Console API requests are throttled, retrying once the Console API accepts them again",
+	}
+}
+
+// NotThrottled returns a condition indicating that the Console API is no
+// longer throttling requests.
+func NotThrottled() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeThrottled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotThrottled,
+	}
+}
+
+// DeletionBlocked returns a condition indicating that the cluster will not be
+// deleted because its deletion protection is enabled.
+func DeletionBlocked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionProtection,
+		Message:            "Deletion protection is enabled; set spec.forProvider.deletionProtection to false to delete the cluster",
+	}
+}
+
+// DeletionAllowed returns a condition indicating that the cluster is no longer
+// protected from deletion.
+func DeletionAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionAllowed,
+	}
+}
+
+// ChangesDue returns a condition indicating that the supplied changes of a
+// cluster are due. The Console API cannot change existing clusters, so they
+// must be made in the Camunda Console.
+func ChangesDue(changes []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChangesDue,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChangesDue,
+		Message:            "The Console API cannot change existing clusters, make these changes in the Camunda Console: " + strings.Join(changes, ", "),
+	}
+}
+
+// NoChangesDue returns a condition indicating that no changes of a cluster
+// are due.
+func NoChangesDue() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChangesDue,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoChangesDue,
+	}
+}
+
+// ComponentReady returns a condition of the supplied type that reflects the
+// supplied status of a cluster component, as reported by the Console API.
+func ComponentReady(t xpv1.ConditionType, status string) xpv1.Condition {
+	c := xpv1.Condition{
+		Type:               t,
+		LastTransitionTime: metav1.Now(),
+	}
+	switch status {
+	case ComponentStatusHealthy:
+		c.Status = corev1.ConditionTrue
+		c.Reason = ReasonComponentHealthy
+	case ComponentStatusCreating:
+		c.Status = corev1.ConditionFalse
+		c.Reason = ReasonComponentCreating
+	case "":
+		c.Status = corev1.ConditionUnknown
+		c.Reason = ReasonComponentNotReported
+		c.Message = "The Console API did not report a status for this component"
+	default:
+		c.Status = corev1.ConditionFalse
+		c.Reason = ReasonComponentUnhealthy
+		c.Message = fmt.Sprintf("The Console API reports the component as %q", status)
+	}
+	return c
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1beta1"
+)
+
+// AnnotationKeyNames is the annotation a v1beta1 ZeebeCluster keeps the
+// v1alpha1 display names of its plan, channel, generation and region in, so
+// that they survive a round trip between the two versions.
+const AnnotationKeyNames = "cc.camunda.crossplane.io/v1alpha1-names"
+
+const (
+	errNotV1beta1 = "not a v1beta1 ZeebeCluster"
+	errNames      = "cannot serialize v1alpha1 names"
+)
+
+// A convertedName is a display name of a v1alpha1 ZeebeCluster and the
+// v1beta1 ID it was converted to.
+type convertedName struct {
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"`
+
+	// Resolved is true if the ID was not set in v1alpha1, but looked up from
+	// the observed cluster.
+	Resolved bool `json:"resolved,omitempty"`
+}
+
+type convertedNames struct {
+	Plan       *convertedName `json:"plan,omitempty"`
+	Channel    *convertedName `json:"channel,omitempty"`
+	Generation *convertedName `json:"generation,omitempty"`
+	Region     *convertedName `json:"region,omitempty"`
+}
+
+// toID returns the v1beta1 ID of a parameter that is set by the supplied name
+// and ID, along with the name to remember for the way back. A name without an
+// ID is resolved to the observed ID if it names the observed parameter.
+func toID(name, id, observedID string, observed bool) (string, *convertedName) {
+	switch {
+	case name == "":
+		return id, nil
+	case id != "":
+		return id, &convertedName{Name: name, ID: id}
+	case observed && observedID != "":
+		return observedID, &convertedName{Name: name, ID: observedID, Resolved: true}
+	}
+	return "", &convertedName{Name: name}
+}
+
+// fromID returns the v1alpha1 name and ID of a parameter that is set by the
+// supplied v1beta1 ID. The remembered name is only used if the ID did not
+// change since it was converted.
+func fromID(id string, n *convertedName) (string, string) {
+	if n == nil || n.ID != id {
+		return "", id
+	}
+	if n.Resolved {
+		return n.Name, ""
+	}
+	return n.Name, id
+}
+
+// ConvertTo converts this ZeebeCluster to the v1beta1 hub version.
+func (src *ZeebeCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint:golint
+	dst, ok := dstRaw.(*v1beta1.ZeebeCluster)
+	if !ok {
+		return errors.New(errNotV1beta1)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Spec.ResourceSpec.DeepCopyInto(&dst.Spec.ResourceSpec)
+	dst.Spec.ManagementPolicy = v1beta1.ManagementPolicy(src.Spec.ManagementPolicy)
+
+	p, o := src.Spec.ForProvider, src.Status.AtProvider
+	n := convertedNames{}
+	dp := &dst.Spec.ForProvider
+	dp.PlanID, n.Plan = toID(p.PlanName, p.PlanId, o.PlanId, p.PlanName == o.PlanName)
+	// Channels are matched by a part of their name, like the controller does.
+	dp.ChannelID, n.Channel = toID(p.ChannelName, p.ChannelId, o.ChannelId, strings.Contains(o.ChannelName, p.ChannelName))
+	dp.GenerationID, n.Generation = toID(p.GenerationName, p.GenerationId, o.GenerationId, p.GenerationName == o.GenerationName)
+	dp.RegionID, n.Region = toID(p.Region, p.RegionId, o.RegionId, p.Region == o.RegionName)
+	dp.DeletionProtection = p.DeletionProtection
+	dp.MaintenanceWindow = nil
+	if w := p.MaintenanceWindow; w != nil {
+		dp.MaintenanceWindow = &v1beta1.MaintenanceWindow{Start: w.Start, Duration: w.Duration, TimeZone: w.TimeZone}
+		for _, d := range w.Days {
+			dp.MaintenanceWindow.Days = append(dp.MaintenanceWindow.Days, v1beta1.Weekday(d))
+		}
+	}
+
+	meta.RemoveAnnotations(dst, AnnotationKeyNames)
+	if n != (convertedNames{}) {
+		b, err := json.Marshal(n)
+		if err != nil {
+			return errors.Wrap(err, errNames)
+		}
+		meta.AddAnnotations(dst, map[string]string{AnnotationKeyNames: string(b)})
+	}
+
+	src.Status.ResourceStatus.DeepCopyInto(&dst.Status.ResourceStatus)
+	dst.Status.AtProvider = v1beta1.ZeebeClusterObservation{
+		ClusterID:      o.ClusterId,
+		OrganizationID: o.OrganizationId,
+		Health: v1beta1.ClusterHealth{
+			Ready:    o.ClusterStatus.Ready,
+			Zeebe:    o.ClusterStatus.ZeebeStatus,
+			Operate:  o.ClusterStatus.OperateStatus,
+			Tasklist: o.ClusterStatus.TaskListStatus,
+			Optimize: o.ClusterStatus.OptimizeStatus,
+		},
+		Plan:       v1beta1.ObservedParameter{ID: o.PlanId, Name: o.PlanName},
+		Channel:    v1beta1.ObservedParameter{ID: o.ChannelId, Name: o.ChannelName},
+		Generation: v1beta1.ObservedParameter{ID: o.GenerationId, Name: o.GenerationName},
+		Region:     v1beta1.ObservedParameter{ID: o.RegionId, Name: o.RegionName},
+		Endpoints: v1beta1.ClusterEndpoints{
+			ZeebeAddress: firstNonEmpty(o.ZeebeAddress, o.ClusterStatus.ZeebeURL),
+			OperateURL:   firstNonEmpty(o.OperateURL, o.ClusterStatus.OperateURL),
+			TasklistURL:  firstNonEmpty(o.TasklistURL, o.ClusterStatus.TaskListURL),
+			OptimizeURL:  firstNonEmpty(o.OptimizeURL, o.ClusterStatus.OptimizeURL),
+		},
+		Created:               o.Created.DeepCopy(),
+		LastUpgrade:           o.LastUpgrade.DeepCopy(),
+		Owner:                 o.Owner,
+		PendingChanges:        append([]string(nil), o.PendingChanges...),
+		NextMaintenanceWindow: o.NextMaintenanceWindow.DeepCopy(),
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1beta1 hub version of a ZeebeCluster to this
+// version. The component URLs that v1alpha1 repeats in its cluster status are
+// taken from the v1beta1 endpoints.
+func (dst *ZeebeCluster) ConvertFrom(srcRaw conversion.Hub) error { // nolint:golint
+	src, ok := srcRaw.(*v1beta1.ZeebeCluster)
+	if !ok {
+		return errors.New(errNotV1beta1)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	n := convertedNames{}
+	if a, ok := dst.GetAnnotations()[AnnotationKeyNames]; ok {
+		// Names that cannot be parsed are dropped rather than making the
+		// ZeebeCluster unreadable in this version; the IDs still identify
+		// the plan, channel, generation and region.
+		_ = json.Unmarshal([]byte(a), &n)
+		meta.RemoveAnnotations(dst, AnnotationKeyNames)
+		if len(dst.GetAnnotations()) == 0 {
+			dst.SetAnnotations(nil)
+		}
+	}
+	src.Spec.ResourceSpec.DeepCopyInto(&dst.Spec.ResourceSpec)
+	dst.Spec.ManagementPolicy = ManagementPolicy(src.Spec.ManagementPolicy)
+
+	p := src.Spec.ForProvider
+	dp := &dst.Spec.ForProvider
+	dp.PlanName, dp.PlanId = fromID(p.PlanID, n.Plan)
+	dp.ChannelName, dp.ChannelId = fromID(p.ChannelID, n.Channel)
+	dp.GenerationName, dp.GenerationId = fromID(p.GenerationID, n.Generation)
+	dp.Region, dp.RegionId = fromID(p.RegionID, n.Region)
+	dp.DeletionProtection = p.DeletionProtection
+	dp.MaintenanceWindow = nil
+	if w := p.MaintenanceWindow; w != nil {
+		dp.MaintenanceWindow = &MaintenanceWindow{Start: w.Start, Duration: w.Duration, TimeZone: w.TimeZone}
+		for _, d := range w.Days {
+			dp.MaintenanceWindow.Days = append(dp.MaintenanceWindow.Days, Weekday(d))
+		}
+	}
+
+	o := src.Status.AtProvider
+	src.Status.ResourceStatus.DeepCopyInto(&dst.Status.ResourceStatus)
+	dst.Status.AtProvider = ZeebeClusterObservation{
+		ClusterId:      o.ClusterID,
+		OrganizationId: o.OrganizationID,
+		PlanId:         o.Plan.ID,
+		PlanName:       o.Plan.Name,
+		ChannelId:      o.Channel.ID,
+		ChannelName:    o.Channel.Name,
+		GenerationId:   o.Generation.ID,
+		GenerationName: o.Generation.Name,
+		RegionId:       o.Region.ID,
+		RegionName:     o.Region.Name,
+		ZeebeAddress:   o.Endpoints.ZeebeAddress,
+		OperateURL:     o.Endpoints.OperateURL,
+		TasklistURL:    o.Endpoints.TasklistURL,
+		OptimizeURL:    o.Endpoints.OptimizeURL,
+		Created:        o.Created.DeepCopy(),
+		LastUpgrade:    o.LastUpgrade.DeepCopy(),
+		Owner:          o.Owner,
+		PendingChanges: append([]string(nil), o.PendingChanges...),
+	}
+	dst.Status.AtProvider.NextMaintenanceWindow = o.NextMaintenanceWindow.DeepCopy()
+	dst.Status.AtProvider.ClusterStatus.Ready = o.Health.Ready
+	dst.Status.AtProvider.ClusterStatus.ZeebeStatus = o.Health.Zeebe
+	dst.Status.AtProvider.ClusterStatus.OperateStatus = o.Health.Operate
+	dst.Status.AtProvider.ClusterStatus.TaskListStatus = o.Health.Tasklist
+	dst.Status.AtProvider.ClusterStatus.OptimizeStatus = o.Health.Optimize
+	dst.Status.AtProvider.ClusterStatus.ZeebeURL = o.Endpoints.ZeebeAddress
+	dst.Status.AtProvider.ClusterStatus.OperateURL = o.Endpoints.OperateURL
+	dst.Status.AtProvider.ClusterStatus.TaskListURL = o.Endpoints.TasklistURL
+	dst.Status.AtProvider.ClusterStatus.OptimizeURL = o.Endpoints.OptimizeURL
+	return nil
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1beta1"
+)
+
+var (
+	created = metav1.NewTime(time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+	observed = ZeebeClusterObservation{
+		ClusterId: "cool-id",
+		ClusterStatus: ClusterStatus{
+			Ready:          "Healthy",
+			ZeebeStatus:    "Healthy",
+			ZeebeURL:       "cool.zeebe.camunda.io:443",
+			OperateStatus:  "Healthy",
+			OperateURL:     "https://operate.camunda.io/cool",
+			TaskListStatus: "Unhealthy",
+			TaskListURL:    "https://tasklist.camunda.io/cool",
+			OptimizeStatus: "Healthy",
+			OptimizeURL:    "https://optimize.camunda.io/cool",
+		},
+		OrganizationId: "cool-org",
+		PlanId:         "plan-development",
+		PlanName:       "Development",
+		ChannelId:      "channel-stable",
+		ChannelName:    "Stable",
+		GenerationId:   "generation-zeebe-1-0-0",
+		GenerationName: "Zeebe 1.0.0",
+		RegionId:       "region-europe-west-1d",
+		RegionName:     "Europe West 1D",
+		ZeebeAddress:   "cool.zeebe.camunda.io:443",
+		OperateURL:     "https://operate.camunda.io/cool",
+		TasklistURL:    "https://tasklist.camunda.io/cool",
+		OptimizeURL:    "https://optimize.camunda.io/cool",
+		Created:        &created,
+		Owner:          "cool-user",
+		PendingChanges: []string{`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`},
+	}
+
+	observedV1beta1 = v1beta1.ZeebeClusterObservation{
+		ClusterID:      "cool-id",
+		OrganizationID: "cool-org",
+		Health:         v1beta1.ClusterHealth{Ready: "Healthy", Zeebe: "Healthy", Operate: "Healthy", Tasklist: "Unhealthy", Optimize: "Healthy"},
+		Plan:           v1beta1.ObservedParameter{ID: "plan-development", Name: "Development"},
+		Channel:        v1beta1.ObservedParameter{ID: "channel-stable", Name: "Stable"},
+		Generation:     v1beta1.ObservedParameter{ID: "generation-zeebe-1-0-0", Name: "Zeebe 1.0.0"},
+		Region:         v1beta1.ObservedParameter{ID: "region-europe-west-1d", Name: "Europe West 1D"},
+		Endpoints: v1beta1.ClusterEndpoints{
+			ZeebeAddress: "cool.zeebe.camunda.io:443",
+			OperateURL:   "https://operate.camunda.io/cool",
+			TasklistURL:  "https://tasklist.camunda.io/cool",
+			OptimizeURL:  "https://optimize.camunda.io/cool",
+		},
+		Created:        &created,
+		Owner:          "cool-user",
+		PendingChanges: []string{`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`},
+	}
+
+	resourceSpec = xpv1.ResourceSpec{
+		ProviderConfigReference:          &xpv1.Reference{Name: "cool-config"},
+		WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: "cool-ns", Name: "cool-zeebe"},
+	}
+)
+
+func TestConvertTo(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		src    *ZeebeCluster
+		want   *v1beta1.ZeebeCluster
+	}{
+		"ObservedNames": {
+			reason: "Names of the observed plan, channel, generation and region should be converted to their IDs.",
+			src: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{"cool": "annotation"}},
+				Spec: ZeebeClusterSpec{
+					ResourceSpec:     resourceSpec,
+					ManagementPolicy: ManagementFullControl,
+					ForProvider: ZeebeClusterParameters{
+						PlanName:           "Development",
+						ChannelName:        "Stable",
+						GenerationName:     "Zeebe 1.1.0",
+						Region:             "Europe West 1D",
+						DeletionProtection: true,
+						MaintenanceWindow: &MaintenanceWindow{
+							Days:     []Weekday{"Saturday", "Sunday"},
+							Start:    "02:00",
+							Duration: metav1.Duration{Duration: 2 * time.Hour},
+							TimeZone: "Europe/Berlin",
+						},
+					},
+				},
+				Status: ZeebeClusterStatus{
+					ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+					AtProvider:     observed,
+				},
+			},
+			want: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{
+					"cool": "annotation",
+					// The generation waits for a maintenance window, so its
+					// name does not name the observed generation.
+					AnnotationKeyNames: `{"plan":{"name":"Development","id":"plan-development","resolved":true},` +
+						`"channel":{"name":"Stable","id":"channel-stable","resolved":true},` +
+						`"generation":{"name":"Zeebe 1.1.0"},` +
+						`"region":{"name":"Europe West 1D","id":"region-europe-west-1d","resolved":true}}`,
+				}},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ResourceSpec:     resourceSpec,
+					ManagementPolicy: v1beta1.ManagementFullControl,
+					ForProvider: v1beta1.ZeebeClusterParameters{
+						PlanID:             "plan-development",
+						ChannelID:          "channel-stable",
+						RegionID:           "region-europe-west-1d",
+						DeletionProtection: true,
+						MaintenanceWindow: &v1beta1.MaintenanceWindow{
+							Days:     []v1beta1.Weekday{"Saturday", "Sunday"},
+							Start:    "02:00",
+							Duration: metav1.Duration{Duration: 2 * time.Hour},
+							TimeZone: "Europe/Berlin",
+						},
+					},
+				},
+				Status: v1beta1.ZeebeClusterStatus{
+					ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+					AtProvider:     observedV1beta1,
+				},
+			},
+		},
+		"IDs": {
+			reason: "IDs should be converted as they are, without remembering any names.",
+			src: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanId: "plan-production-s", RegionId: "region-us-east-1b"},
+				},
+			},
+			want: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{PlanID: "plan-production-s", RegionID: "region-us-east-1b"},
+				},
+			},
+		},
+		"NamesAndIDs": {
+			reason: "IDs should take precedence over names, which should be remembered along with them.",
+			src: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanName: "Production S", PlanId: "plan-production-s"},
+				},
+			},
+			want: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{
+					AnnotationKeyNames: `{"plan":{"name":"Production S","id":"plan-production-s"}}`,
+				}},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{PlanID: "plan-production-s"},
+				},
+			},
+		},
+		"PartialChannelName": {
+			reason: "A channel should be matched by a part of its name, like the controller does.",
+			src: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{ChannelName: "Alpha"},
+				},
+				Status: ZeebeClusterStatus{
+					AtProvider: ZeebeClusterObservation{ChannelId: "channel-alpha", ChannelName: "Alpha (Preview)"},
+				},
+			},
+			want: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{
+					AnnotationKeyNames: `{"channel":{"name":"Alpha","id":"channel-alpha","resolved":true}}`,
+				}},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{ChannelID: "channel-alpha"},
+				},
+				Status: v1beta1.ZeebeClusterStatus{
+					AtProvider: v1beta1.ZeebeClusterObservation{Channel: v1beta1.ObservedParameter{ID: "channel-alpha", Name: "Alpha (Preview)"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &v1beta1.ZeebeCluster{}
+			if err := tc.src.ConvertTo(got); err != nil {
+				t.Fatalf("\n%s\nConvertTo(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConvertTo(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConvertFrom(t *testing.T) {
+	names := `{"plan":{"name":"Development","id":"plan-development","resolved":true},"region":{"name":"Europe West 1D","id":"region-europe-west-1d"}}`
+
+	cases := map[string]struct {
+		reason string
+		src    *v1beta1.ZeebeCluster
+		want   *ZeebeCluster
+	}{
+		"IDs": {
+			reason: "IDs should be converted as they are, and the URLs of the cluster status taken from the endpoints.",
+			src: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{PlanID: "plan-development", GenerationID: "generation-zeebe-1-1-0"},
+				},
+				Status: v1beta1.ZeebeClusterStatus{AtProvider: observedV1beta1},
+			},
+			want: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanId: "plan-development", GenerationId: "generation-zeebe-1-1-0"},
+				},
+				Status: ZeebeClusterStatus{AtProvider: observed},
+			},
+		},
+		"RememberedNames": {
+			reason: "Remembered names should be restored, and the annotation they were remembered in removed.",
+			src: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{AnnotationKeyNames: names}},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{PlanID: "plan-development", RegionID: "region-europe-west-1d"},
+				},
+			},
+			want: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanName: "Development", Region: "Europe West 1D", RegionId: "region-europe-west-1d"},
+				},
+			},
+		},
+		"ChangedIDs": {
+			reason: "Remembered names should be dropped if the ID they were converted to changed.",
+			src: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{AnnotationKeyNames: names, "cool": "annotation"}},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{PlanID: "plan-production-s", RegionID: "region-europe-west-1d"},
+				},
+			},
+			want: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{"cool": "annotation"}},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanId: "plan-production-s", Region: "Europe West 1D", RegionId: "region-europe-west-1d"},
+				},
+			},
+		},
+		"MalformedNames": {
+			reason: "Remembered names that cannot be parsed should be dropped rather than fail the conversion.",
+			src: &v1beta1.ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{AnnotationKeyNames: "{"}},
+				Spec: v1beta1.ZeebeClusterSpec{
+					ForProvider: v1beta1.ZeebeClusterParameters{PlanID: "plan-development"},
+				},
+			},
+			want: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanId: "plan-development"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &ZeebeCluster{}
+			if err := got.ConvertFrom(tc.src); err != nil {
+				t.Fatalf("\n%s\nConvertFrom(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConvertFrom(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cr     *ZeebeCluster
+	}{
+		"New": {
+			reason: "A ZeebeCluster that was not observed yet should survive a round trip.",
+			cr: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ResourceSpec: resourceSpec,
+					ForProvider:  ZeebeClusterParameters{PlanName: "Development", ChannelName: "Stable", Region: "Europe West 1D"},
+				},
+			},
+		},
+		"Observed": {
+			reason: "An observed ZeebeCluster with pending changes should survive a round trip.",
+			cr: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: map[string]string{"cool": "annotation"}},
+				Spec: ZeebeClusterSpec{
+					ResourceSpec:     resourceSpec,
+					ManagementPolicy: ManagementObserveOnly,
+					ForProvider: ZeebeClusterParameters{
+						PlanName:       "Development",
+						ChannelName:    "Stab",
+						GenerationName: "Zeebe 1.1.0",
+						Region:         "Europe West 1D",
+						MaintenanceWindow: &MaintenanceWindow{
+							Start:    "02:00",
+							Duration: metav1.Duration{Duration: time.Hour},
+						},
+					},
+				},
+				Status: ZeebeClusterStatus{AtProvider: observed},
+			},
+		},
+		"NamesAndIDs": {
+			reason: "A ZeebeCluster with both names and IDs should survive a round trip.",
+			cr: &ZeebeCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Spec: ZeebeClusterSpec{
+					ForProvider: ZeebeClusterParameters{PlanName: "Production S", PlanId: "plan-production-s", GenerationId: "generation-zeebe-1-1-0"},
+				},
+				Status: ZeebeClusterStatus{AtProvider: observed},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hub := &v1beta1.ZeebeCluster{}
+			if err := tc.cr.ConvertTo(hub); err != nil {
+				t.Fatalf("\n%s\nConvertTo(...): %v", tc.reason, err)
+			}
+			got := &ZeebeCluster{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("\n%s\nConvertFrom(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.cr, got); diff != "" {
+				t.Errorf("\n%s\nConvertFrom(ConvertTo(...)): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
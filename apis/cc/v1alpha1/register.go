@@ -45,6 +45,15 @@ var (
 	ZeebeClusterGroupVersionKind = SchemeGroupVersion.WithKind(ZeebeClusterKind)
 )
 
+// ZeebeClusterClaim type metadata.
+var (
+	ZeebeClusterClaimKind             = reflect.TypeOf(ZeebeClusterClaim{}).Name()
+	ZeebeClusterClaimGroupKind        = schema.GroupKind{Group: Group, Kind: ZeebeClusterClaimKind}.String()
+	ZeebeClusterClaimKindAPIVersion   = ZeebeClusterClaimKind + "." + SchemeGroupVersion.String()
+	ZeebeClusterClaimGroupVersionKind = SchemeGroupVersion.WithKind(ZeebeClusterClaimKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&ZeebeCluster{}, &ZeebeClusterList{})
+	SchemeBuilder.Register(&ZeebeClusterClaim{}, &ZeebeClusterClaimList{})
 }
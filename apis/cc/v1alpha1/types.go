@@ -17,7 +17,6 @@ limitations under the License.
 package v1alpha1
 
 import (
-	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -33,18 +32,168 @@ type ZeebeClusterParameters struct {
 	GenerationName string `json:"generationName"`
 	// +kubebuilder:validation:Optional
 	PlanName string `json:"planName"`
+	// PlanId is the ID of the cluster plan. It takes precedence over
+	// PlanName.
+	// +kubebuilder:validation:Optional
+	PlanId string `json:"planId,omitempty"`
+	// ChannelId is the ID of the channel. It takes precedence over
+	// ChannelName.
+	// +kubebuilder:validation:Optional
+	ChannelId string `json:"channelId,omitempty"`
+	// GenerationId is the ID of the generation. It takes precedence over
+	// GenerationName.
+	// +kubebuilder:validation:Optional
+	GenerationId string `json:"generationId,omitempty"`
+	// RegionId is the ID of the region. It takes precedence over Region.
+	// +kubebuilder:validation:Optional
+	RegionId string `json:"regionId,omitempty"`
+	// DeletionProtection prevents the cluster from being deleted while it is
+	// true. It must be set to false before the cluster can be deleted.
+	// +kubebuilder:validation:Optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+	// MaintenanceWindow in which disruptive changes, such as generation
+	// upgrades and plan changes, are due. Such changes are due right away if
+	// no window is set. The Console API cannot change existing clusters, so
+	// changes that are due are reported in an event, to be made in the
+	// Camunda Console.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// A Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// A MaintenanceWindow is a recurring period of time in which disruptive
+// changes may be made to a cluster.
+type MaintenanceWindow struct {
+	// Days of the week the window opens on. It opens every day if none are
+	// set.
+	// +kubebuilder:validation:Optional
+	Days []Weekday `json:"days,omitempty"`
+	// Start is the time of day the window opens at, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// Duration the window stays open for, such as 2h or 90m. It must be at
+	// least a minute, written in hours, minutes and seconds.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^(0*[1-9][0-9]*h([0-9]+m)?|([0-9]+h)?0*[1-9][0-9]*m)([0-9]+s)?$`
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone the start time is in, as an IANA time zone name such as
+	// Europe/Berlin.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ClusterStatus is the health of a cluster and its components, and the
+// addresses of those components, as last reported by the Console API. Its
+// fields keep the names the Console API reports them with. None of them are
+// enumerated or limited in length, so that a value the Console API starts to
+// report cannot make the status invalid and fail the whole status update.
+type ClusterStatus struct {
+	// Ready is the health of the cluster as a whole, such as Healthy,
+	// Creating or Unhealthy.
+	// +kubebuilder:validation:Optional
+	Ready string `json:"ready,omitempty"`
+	// ZeebeStatus is the health of the cluster's Zeebe brokers and gateway.
+	// +kubebuilder:validation:Optional
+	ZeebeStatus string `json:"zeebeStatus,omitempty"`
+	// ZeebeURL is the address of the cluster's Zeebe gateway.
+	// +kubebuilder:validation:Optional
+	ZeebeURL string `json:"zeebeUrl,omitempty"`
+	// OperateStatus is the health of the cluster's Operate.
+	// +kubebuilder:validation:Optional
+	OperateStatus string `json:"operateStatus,omitempty"`
+	// OperateURL is the URL of the cluster's Operate.
+	// +kubebuilder:validation:Optional
+	OperateURL string `json:"operateUrl,omitempty"`
+	// TaskListStatus is the health of the cluster's Tasklist.
+	// +kubebuilder:validation:Optional
+	TaskListStatus string `json:"tasklistStatus,omitempty"`
+	// TaskListURL is the URL of the cluster's Tasklist.
+	// +kubebuilder:validation:Optional
+	TaskListURL string `json:"tasklistUrl,omitempty"`
+	// OptimizeStatus is the health of the cluster's Optimize.
+	// +kubebuilder:validation:Optional
+	OptimizeStatus string `json:"optimizeStatus,omitempty"`
+	// OptimizeURL is the URL of the cluster's Optimize.
+	// +kubebuilder:validation:Optional
+	OptimizeURL string `json:"optimizeUrl,omitempty"`
 }
 
 // ZeebeClusterObservation are the observable fields of a ZeebeCluster.
 type ZeebeClusterObservation struct {
 	ClusterId string `json:"clusterId"`
-	ClusterStatus cc.ClusterStatus `json:"clusterStatus"`
+	// ClusterStatus is the health of the cluster and its components.
+	// +kubebuilder:validation:Optional
+	ClusterStatus ClusterStatus `json:"clusterStatus"`
+	// OrganizationId is the Camunda Cloud organization the cluster belongs to.
+	OrganizationId string `json:"organizationId,omitempty"`
+	// PlanName is the cluster plan the cluster was last observed with.
+	PlanName string `json:"planName,omitempty"`
+	// GenerationName is the generation the cluster was last observed with.
+	GenerationName string `json:"generationName,omitempty"`
+	// PlanId is the ID of the cluster plan.
+	PlanId string `json:"planId,omitempty"`
+	// ChannelId is the ID of the channel.
+	ChannelId string `json:"channelId,omitempty"`
+	// ChannelName is the display name of the channel.
+	ChannelName string `json:"channelName,omitempty"`
+	// GenerationId is the ID of the generation.
+	GenerationId string `json:"generationId,omitempty"`
+	// RegionId is the ID of the region.
+	RegionId string `json:"regionId,omitempty"`
+	// RegionName is the display name of the region.
+	RegionName string `json:"regionName,omitempty"`
+	// ZeebeAddress is the address of the cluster's Zeebe gateway.
+	ZeebeAddress string `json:"zeebeAddress,omitempty"`
+	// OperateURL is the URL of the cluster's Operate.
+	OperateURL string `json:"operateUrl,omitempty"`
+	// TasklistURL is the URL of the cluster's Tasklist.
+	TasklistURL string `json:"tasklistUrl,omitempty"`
+	// OptimizeURL is the URL of the cluster's Optimize.
+	OptimizeURL string `json:"optimizeUrl,omitempty"`
+	// Created is when the cluster was created.
+	Created *metav1.Time `json:"created,omitempty"`
+	// LastUpgrade is when the cluster was last upgraded to a new generation.
+	LastUpgrade *metav1.Time `json:"lastUpgrade,omitempty"`
+	// Owner is the Camunda Cloud user who created the cluster.
+	Owner string `json:"owner,omitempty"`
+	// PendingChanges are changes needed to make the cluster match its
+	// parameters. They must be made in the Camunda Console.
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+	// NextMaintenanceWindow is when the next maintenance window opens, if
+	// changes are pending.
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
 }
 
+// A ManagementPolicy determines what the provider may do to a cluster.
+// +kubebuilder:validation:Enum=FullControl;ObserveOnly
+type ManagementPolicy string
+
+// Management policies.
+const (
+	// ManagementFullControl lets the provider create and delete the
+	// cluster.
+	ManagementFullControl ManagementPolicy = "FullControl"
+
+	// ManagementObserveOnly lets the provider only observe an existing
+	// cluster. It is never created, updated or deleted, not even when the
+	// ZeebeCluster is deleted.
+	ManagementObserveOnly ManagementPolicy = "ObserveOnly"
+)
+
 // A ZeebeClusterSpec defines the desired state of a ZeebeCluster.
 type ZeebeClusterSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       ZeebeClusterParameters `json:"forProvider"`
+	// ManagementPolicy determines what the provider may do to the cluster.
+	// Use ObserveOnly for clusters that are owned by someone else, but should
+	// be visible and referenceable in this control plane.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=FullControl
+	ManagementPolicy ManagementPolicy       `json:"managementPolicy,omitempty"`
+	ForProvider      ZeebeClusterParameters `json:"forProvider"`
 }
 
 // A ZeebeClusterStatus represents the observed state of a ZeebeCluster.
@@ -55,6 +204,7 @@ type ZeebeClusterStatus struct {
 
 // +kubebuilder:object:root=true
 // A ZeebeCluster is a remote ZeebeCluster in Camunda Cloud API type
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.clusterStatus.ready"
 // +kubebuilder:printcolumn:name="CLUSTER ID",type="string",JSONPath=".status.atProvider.clusterId"
@@ -62,6 +212,11 @@ type ZeebeClusterStatus struct {
 // +kubebuilder:printcolumn:name="CHANNEL",type="string",JSONPath=".spec.forProvider.channelName"
 // +kubebuilder:printcolumn:name="GENERATION",type="string",JSONPath=".spec.forProvider.generationName"
 // +kubebuilder:printcolumn:name="REGION",type="string",JSONPath=".spec.forProvider.region"
+// +kubebuilder:printcolumn:name="ZEEBE",type="string",JSONPath=".status.conditions[?(@.type=='ZeebeReady')].status",priority=1
+// +kubebuilder:printcolumn:name="OPERATE",type="string",JSONPath=".status.conditions[?(@.type=='OperateReady')].status",priority=1
+// +kubebuilder:printcolumn:name="TASKLIST",type="string",JSONPath=".status.conditions[?(@.type=='TasklistReady')].status",priority=1
+// +kubebuilder:printcolumn:name="OPTIMIZE",type="string",JSONPath=".status.conditions[?(@.type=='OptimizeReady')].status",priority=1
+// +kubebuilder:printcolumn:name="ORGANIZATION",type="string",JSONPath=".status.atProvider.organizationId",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,shortName=zb
 type ZeebeCluster struct {
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClusterStatusJSON(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		stored string
+		want   ClusterStatus
+		json   string
+	}{
+		"Stored": {
+			reason: "A status stored by earlier versions of the provider should be read, and written with the same keys.",
+			stored: `{"ready":"Healthy","zeebeStatus":"Healthy","zeebeUrl":"cool.zeebe.camunda.io:443",` +
+				`"operateStatus":"Healthy","operateUrl":"https://operate","tasklistStatus":"Unhealthy","tasklistUrl":"https://tasklist"}`,
+			want: ClusterStatus{
+				Ready:          "Healthy",
+				ZeebeStatus:    "Healthy",
+				ZeebeURL:       "cool.zeebe.camunda.io:443",
+				OperateStatus:  "Healthy",
+				OperateURL:     "https://operate",
+				TaskListStatus: "Unhealthy",
+				TaskListURL:    "https://tasklist",
+			},
+			json: `{"ready":"Healthy","zeebeStatus":"Healthy","zeebeUrl":"cool.zeebe.camunda.io:443",` +
+				`"operateStatus":"Healthy","operateUrl":"https://operate","tasklistStatus":"Unhealthy","tasklistUrl":"https://tasklist"}`,
+		},
+		"Optimize": {
+			reason: "The health and URL of Optimize should be read and written.",
+			stored: `{"ready":"Healthy","optimizeStatus":"Healthy","optimizeUrl":"https://optimize"}`,
+			want:   ClusterStatus{Ready: "Healthy", OptimizeStatus: "Healthy", OptimizeURL: "https://optimize"},
+			json:   `{"ready":"Healthy","optimizeStatus":"Healthy","optimizeUrl":"https://optimize"}`,
+		},
+		"StoredEmpty": {
+			reason: "Empty values stored by earlier versions of the provider should be read, and omitted when written.",
+			stored: `{"ready":"Creating","zeebeStatus":"","zeebeUrl":"","operateStatus":"","operateUrl":"","tasklistStatus":"","tasklistUrl":""}`,
+			want:   ClusterStatus{Ready: "Creating"},
+			json:   `{"ready":"Creating"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ClusterStatus{}
+			if err := json.Unmarshal([]byte(tc.stored), &got); err != nil {
+				t.Fatalf("\n%s\njson.Unmarshal(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\njson.Unmarshal(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("\n%s\njson.Marshal(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.json, string(b)); diff != "" {
+				t.Errorf("\n%s\njson.Marshal(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A ZeebeClusterClaimSpec defines the desired state of a ZeebeClusterClaim.
+type ZeebeClusterClaimSpec struct {
+	// ProviderConfigReference specifies the ProviderConfig the claimed
+	// cluster is managed with. The claim's namespace must be allowed to use
+	// it, by an RBAC role that grants the use verb on it.
+	// +kubebuilder:default={"name": "default"}
+	ProviderConfigReference *xpv1.Reference `json:"providerConfigRef,omitempty"`
+
+	// WriteConnectionSecretToReference specifies the name of a Secret in the
+	// claim's namespace to which the connection details of the claimed
+	// cluster are written.
+	// +kubebuilder:validation:Optional
+	WriteConnectionSecretToReference *xpv1.LocalSecretReference `json:"writeConnectionSecretToRef,omitempty"`
+
+	// DeletionPolicy specifies what happens to the claimed cluster when the
+	// claim is deleted. It is deleted by default, and kept if Orphan.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	ForProvider ZeebeClusterParameters `json:"forProvider"`
+}
+
+// A ZeebeClusterClaimStatus represents the observed state of a
+// ZeebeClusterClaim.
+type ZeebeClusterClaimStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// ClusterReference refers to the ZeebeCluster the claim is bound to.
+	ClusterReference *xpv1.Reference `json:"clusterRef,omitempty"`
+
+	// AtProvider is the observed state of the claimed cluster.
+	AtProvider ZeebeClusterObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ZeebeClusterClaim requests a ZeebeCluster from within a namespace. It
+// lets application teams provision clusters without access to cluster scoped
+// resources; the connection secret of the claimed cluster is written to the
+// claim's namespace.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".status.clusterRef.name"
+// +kubebuilder:printcolumn:name="CLUSTER ID",type="string",JSONPath=".status.atProvider.clusterId"
+// +kubebuilder:printcolumn:name="CONNECTION-SECRET",type="string",JSONPath=".spec.writeConnectionSecretToRef.name",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,shortName=zbc
+type ZeebeClusterClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ZeebeClusterClaimSpec   `json:"spec"`
+	Status ZeebeClusterClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ZeebeClusterClaimList contains a list of ZeebeClusterClaims.
+type ZeebeClusterClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ZeebeClusterClaim `json:"items"`
+}
+
+// GetCondition of this ZeebeClusterClaim.
+func (cm *ZeebeClusterClaim) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return cm.Status.GetCondition(ct)
+}
+
+// SetConditions of this ZeebeClusterClaim.
+func (cm *ZeebeClusterClaim) SetConditions(c ...xpv1.Condition) {
+	cm.Status.SetConditions(c...)
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ZeebeClusterParameters are the configurable fields of a ZeebeCluster. The
+// plan, channel, generation and region are identified by the IDs the Console
+// API lists them with, which unlike their display names never change. Those
+// that are not set are filled in from the cluster once it exists.
+type ZeebeClusterParameters struct {
+	// PlanID is the ID of the cluster plan.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	PlanID string `json:"planId,omitempty"`
+
+	// ChannelID is the ID of the channel the cluster gets its generations
+	// from. The Console's default channel is used if it is not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	ChannelID string `json:"channelId,omitempty"`
+
+	// GenerationID is the ID of the generation the cluster runs. The
+	// channel's default generation is used if it is not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	GenerationID string `json:"generationId,omitempty"`
+
+	// RegionID is the ID of the region the cluster runs in. It cannot be
+	// changed once the cluster exists.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	RegionID string `json:"regionId,omitempty"`
+
+	// DeletionProtection prevents the cluster from being deleted while it is
+	// true. It must be set to false before the cluster can be deleted.
+	// +kubebuilder:validation:Optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// MaintenanceWindow in which disruptive changes, such as generation
+	// upgrades and plan changes, are due. Such changes are due right away if
+	// no window is set. The Console API cannot change existing clusters, so
+	// changes that are due are reported in an event, to be made in the
+	// Camunda Console.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// A Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// A MaintenanceWindow is a recurring period of time in which disruptive
+// changes may be made to a cluster.
+type MaintenanceWindow struct {
+	// Days of the week the window opens on. It opens every day if none are
+	// set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=7
+	// +listType=set
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of day the window opens at, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration the window stays open for, such as 2h or 90m. It must be at
+	// least a minute, written in hours, minutes and seconds.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^(0*[1-9][0-9]*h([0-9]+m)?|([0-9]+h)?0*[1-9][0-9]*m)([0-9]+s)?$`
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone the start time is in, as an IANA time zone name such as
+	// Europe/Berlin.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ClusterHealth is the health of a cluster and its components, as reported
+// by the Console API.
+type ClusterHealth struct {
+	// Ready is the health of the cluster as a whole, such as Healthy,
+	// Creating or Unhealthy.
+	Ready string `json:"ready,omitempty"`
+
+	// Zeebe is the health of the cluster's Zeebe brokers and gateway.
+	Zeebe string `json:"zeebe,omitempty"`
+
+	// Operate is the health of the cluster's Operate.
+	Operate string `json:"operate,omitempty"`
+
+	// Tasklist is the health of the cluster's Tasklist.
+	Tasklist string `json:"tasklist,omitempty"`
+
+	// Optimize is the health of the cluster's Optimize.
+	Optimize string `json:"optimize,omitempty"`
+}
+
+// An ObservedParameter is a plan, channel, generation or region a cluster was
+// observed with.
+type ObservedParameter struct {
+	// ID of the parameter.
+	ID string `json:"id,omitempty"`
+
+	// Name of the parameter, as displayed by the Camunda Cloud Console.
+	Name string `json:"name,omitempty"`
+}
+
+// ClusterEndpoints are the addresses a cluster's components are reachable
+// at.
+type ClusterEndpoints struct {
+	// ZeebeAddress is the address of the cluster's Zeebe gateway.
+	ZeebeAddress string `json:"zeebeAddress,omitempty"`
+
+	// OperateURL is the URL of the cluster's Operate.
+	OperateURL string `json:"operateUrl,omitempty"`
+
+	// TasklistURL is the URL of the cluster's Tasklist.
+	TasklistURL string `json:"tasklistUrl,omitempty"`
+
+	// OptimizeURL is the URL of the cluster's Optimize.
+	OptimizeURL string `json:"optimizeUrl,omitempty"`
+}
+
+// ZeebeClusterObservation are the observable fields of a ZeebeCluster.
+type ZeebeClusterObservation struct {
+	// ClusterID is the ID of the cluster.
+	ClusterID string `json:"clusterId,omitempty"`
+
+	// OrganizationID is the Camunda Cloud organization the cluster belongs
+	// to.
+	OrganizationID string `json:"organizationId,omitempty"`
+
+	// Health of the cluster and its components.
+	Health ClusterHealth `json:"health,omitempty"`
+
+	// Plan is the cluster plan the cluster was last observed with.
+	Plan ObservedParameter `json:"plan,omitempty"`
+
+	// Channel is the channel the cluster was last observed with.
+	Channel ObservedParameter `json:"channel,omitempty"`
+
+	// Generation is the generation the cluster was last observed with.
+	Generation ObservedParameter `json:"generation,omitempty"`
+
+	// Region is the region the cluster runs in.
+	Region ObservedParameter `json:"region,omitempty"`
+
+	// Endpoints of the cluster's components.
+	Endpoints ClusterEndpoints `json:"endpoints,omitempty"`
+
+	// Created is when the cluster was created.
+	Created *metav1.Time `json:"created,omitempty"`
+
+	// LastUpgrade is when the cluster was last upgraded to a new generation.
+	LastUpgrade *metav1.Time `json:"lastUpgrade,omitempty"`
+
+	// Owner is the Camunda Cloud user who created the cluster.
+	Owner string `json:"owner,omitempty"`
+
+	// PendingChanges are changes needed to make the cluster match its
+	// parameters. They must be made in the Camunda Console.
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+
+	// NextMaintenanceWindow is when the next maintenance window opens, if
+	// changes are pending.
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+}
+
+// A ManagementPolicy determines what the provider may do to a cluster.
+// +kubebuilder:validation:Enum=FullControl;ObserveOnly
+type ManagementPolicy string
+
+// Management policies.
+const (
+	// ManagementFullControl lets the provider create and delete the
+	// cluster.
+	ManagementFullControl ManagementPolicy = "FullControl"
+
+	// ManagementObserveOnly lets the provider only observe an existing
+	// cluster. It is never created, updated or deleted, not even when the
+	// ZeebeCluster is deleted.
+	ManagementObserveOnly ManagementPolicy = "ObserveOnly"
+)
+
+// A ZeebeClusterSpec defines the desired state of a ZeebeCluster.
+type ZeebeClusterSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// ManagementPolicy determines what the provider may do to the cluster.
+	// Use ObserveOnly for clusters that are owned by someone else, but should
+	// be visible and referenceable in this control plane.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=FullControl
+	ManagementPolicy ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	ForProvider ZeebeClusterParameters `json:"forProvider"`
+}
+
+// A ZeebeClusterStatus represents the observed state of a ZeebeCluster.
+type ZeebeClusterStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ZeebeClusterObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// A ZeebeCluster is a remote ZeebeCluster in Camunda Cloud API type
+// +kubebuilder:unservedversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.health.ready"
+// +kubebuilder:printcolumn:name="CLUSTER ID",type="string",JSONPath=".status.atProvider.clusterId"
+// +kubebuilder:printcolumn:name="PLAN",type="string",JSONPath=".status.atProvider.plan.name"
+// +kubebuilder:printcolumn:name="CHANNEL",type="string",JSONPath=".status.atProvider.channel.name"
+// +kubebuilder:printcolumn:name="GENERATION",type="string",JSONPath=".status.atProvider.generation.name"
+// +kubebuilder:printcolumn:name="REGION",type="string",JSONPath=".status.atProvider.region.name"
+// +kubebuilder:printcolumn:name="ZEEBE",type="string",JSONPath=".status.conditions[?(@.type=='ZeebeReady')].status",priority=1
+// +kubebuilder:printcolumn:name="OPERATE",type="string",JSONPath=".status.conditions[?(@.type=='OperateReady')].status",priority=1
+// +kubebuilder:printcolumn:name="TASKLIST",type="string",JSONPath=".status.conditions[?(@.type=='TasklistReady')].status",priority=1
+// +kubebuilder:printcolumn:name="OPTIMIZE",type="string",JSONPath=".status.conditions[?(@.type=='OptimizeReady')].status",priority=1
+// +kubebuilder:printcolumn:name="ORGANIZATION",type="string",JSONPath=".status.atProvider.organizationId",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,shortName=zb
+type ZeebeCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ZeebeClusterSpec   `json:"spec"`
+	Status ZeebeClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ZeebeClusterList contains a list of ZeebeClusters
+type ZeebeClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ZeebeCluster `json:"items"`
+}
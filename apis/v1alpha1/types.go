@@ -25,6 +25,76 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// OrganizationID is the Camunda Cloud organization the credentials must
+	// belong to. Resources are never reconciled with credentials of another
+	// organization when it is set.
+	// +optional
+	OrganizationID string `json:"organizationId,omitempty"`
+
+	// Transport configures how the provider reaches the Camunda Cloud APIs,
+	// for example through an HTTP(S) proxy.
+	// +optional
+	Transport *Transport `json:"transport,omitempty"`
+
+	// RateLimit configures the client-side rate limit applied to Console API
+	// requests made with this ProviderConfig.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+	// Endpoints overrides where the Camunda Cloud APIs are reached, for
+	// example to use a fake Console API in tests or local development.
+	// +optional
+	Endpoints *Endpoints `json:"endpoints,omitempty"`
+}
+
+// Endpoints are the URLs of the Camunda Cloud APIs.
+type Endpoints struct {
+	// Login is the URL of the OAuth token endpoint that client credentials
+	// are exchanged at.
+	// +kubebuilder:default="https://login.cloud.camunda.io/oauth/token"
+	// +optional
+	Login string `json:"login,omitempty"`
+
+	// API is the base URL of the Console API.
+	// +kubebuilder:default="https://api.cloud.camunda.io"
+	// +optional
+	API string `json:"api,omitempty"`
+}
+
+// RateLimit configures a token bucket for Console API requests.
+type RateLimit struct {
+	// RequestsPerMinute is the sustained number of requests per minute.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	// +optional
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+
+	// Burst is the number of requests that may be sent at once.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	Burst int `json:"burst,omitempty"`
+}
+
+// Transport configures the HTTP transport used to reach Camunda Cloud.
+type Transport struct {
+	// ProxyURL is the URL of an HTTP(S) proxy through which all requests to
+	// Camunda Cloud are sent, e.g. http://proxy.example.com:3128.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// NoProxy lists hosts, domains, IP addresses or CIDRs that are reached
+	// without the proxy, using the same syntax as the NO_PROXY environment
+	// variable.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// CABundleSecretRef references a Secret key holding PEM encoded
+	// certificates that are trusted in addition to the system roots, such as
+	// the CA of a TLS-intercepting proxy.
+	// +optional
+	CABundleSecretRef *xpv1.SecretKeySelector `json:"caBundleSecretRef,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -34,6 +104,32 @@ type ProviderCredentials struct {
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
+
+	// TokenExchange configures how the provider's projected service account
+	// token is exchanged for a Camunda access token. It is required when the
+	// source is InjectedIdentity.
+	// +optional
+	TokenExchange *TokenExchange `json:"tokenExchange,omitempty"`
+}
+
+// TokenExchange configures an OIDC token exchange (RFC 8693) used to obtain a
+// Camunda access token without a long-lived client secret. The provider
+// presents the service account token projected into its pod at
+// /var/run/secrets/camunda.crossplane.io/serviceaccount/token, which must have
+// the audience camunda.crossplane.io, as the subject token.
+type TokenExchange struct {
+	// Endpoint is the URL of the OIDC token-exchange endpoint.
+	Endpoint string `json:"endpoint"`
+
+	// ClientID identifies the provider to the token-exchange endpoint, if the
+	// endpoint requires it.
+	// +optional
+	ClientID string `json:"clientId,omitempty"`
+
+	// Audience of the requested Camunda access token.
+	// +kubebuilder:default="api.cloud.camunda.io"
+	// +optional
+	Audience string `json:"audience,omitempty"`
 }
 
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
@@ -46,6 +142,7 @@ type ProviderConfigStatus struct {
 // A ProviderConfig configures a Template provider.
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="ORGANIZATION",type="string",JSONPath=".spec.organizationId"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster
 type ProviderConfig struct {
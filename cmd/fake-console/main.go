@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/salaboy/provider-camunda-cloud/internal/fake/console"
+)
+
+func main() {
+	var (
+		app          = kingpin.New(filepath.Base(os.Args[0]), "A fake Camunda Cloud Console API for tests and local development.").DefaultEnvars()
+		debug        = app.Flag("debug", "Log every request.").Short('d').Bool()
+		listen       = app.Flag("listen", "Address to serve the fake Console API on.").Default(":8081").String()
+		clientID     = app.Flag("client-id", "Client ID that access tokens are issued for.").Default(console.DefaultClientID).String()
+		clientSecret = app.Flag("client-secret", "Client secret that access tokens are issued for.").Default(console.DefaultClientSecret).String()
+		organization = app.Flag("organization", "Organization that access tokens are issued for.").Default(console.DefaultOrganizationID).String()
+		tokenTTL     = app.Flag("token-ttl", "How long access tokens are valid for.").Default(console.DefaultTokenTTL.String()).Duration()
+		creatingFor  = app.Flag("creating-for", "How long new clusters are creating for.").Default(console.DefaultCreatingFor.String()).Duration()
+		deletingFor  = app.Flag("deleting-for", "How long deleted clusters are deleting for.").Default(console.DefaultDeletingFor.String()).Duration()
+
+		latency           = app.Flag("fault-latency", "Delay every request by this long.").Duration()
+		serverErrors      = app.Flag("fault-server-errors", "Fail this many requests with a server error.").Int()
+		serverErrorStatus = app.Flag("fault-server-error-status", "HTTP status of injected server errors.").Default("500").Int()
+		throttled         = app.Flag("fault-throttled", "Answer this many requests with HTTP 429.").Int()
+		retryAfter        = app.Flag("fault-retry-after", "Retry-After of throttled requests.").Default("30s").Duration()
+		expireTokensAfter = app.Flag("fault-expire-tokens-after", "Expire all access tokens once this many API requests were served.").Int()
+		partialCreates    = app.Flag("fault-partial-creates", "Create the clusters of this many create requests, but fail the requests.").Int()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	log := logging.NewLogrLogger(zap.New(zap.UseDevMode(*debug)).WithName("fake-console"))
+
+	var h http.Handler = console.New(
+		console.WithCredentials(*clientID, *clientSecret),
+		console.WithOrganization(*organization),
+		console.WithTokenTTL(*tokenTTL),
+		console.WithCreatingFor(*creatingFor),
+		console.WithDeletingFor(*deletingFor),
+		console.WithFaults(console.Faults{
+			Latency:           *latency,
+			ServerErrors:      *serverErrors,
+			ServerErrorStatus: *serverErrorStatus,
+			Throttled:         *throttled,
+			RetryAfter:        *retryAfter,
+			ExpireTokensAfter: *expireTokensAfter,
+			PartialCreates:    *partialCreates,
+		}),
+	)
+	if *debug {
+		next := h
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			log.Debug("Served request", "method", r.Method, "path", r.URL.Path, "duration", time.Since(start))
+		})
+	}
+
+	log.Info("Serving fake Console API", "address", *listen, "login-path", console.LoginPath)
+	kingpin.FatalIfError(http.ListenAndServe(*listen, h), "Cannot serve fake Console API")
+}
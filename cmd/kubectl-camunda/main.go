@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/salaboy/provider-camunda-cloud/apis"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/zeebecluster"
+	"github.com/salaboy/provider-camunda-cloud/internal/plugin"
+)
+
+func main() {
+	var (
+		app            = kingpin.New(filepath.Base(os.Args[0]), "Inspect the clusters of a Camunda Cloud organization and import them as ZeebeClusters.").DefaultEnvars()
+		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		kubeconfig     = app.Flag("kubeconfig", "Path to the kubeconfig file to use. Defaults to the kubectl defaults.").String()
+		kubeContext    = app.Flag("context", "Kubeconfig context to use.").String()
+		providerConfig = app.Flag("provider-config", "ProviderConfig whose credentials are used to call the Console API.").Short('p').Default("default").String()
+		timeout        = app.Flag("timeout", "How long to wait for the API server and the Console API.").Default("1m").Duration()
+
+		clustersCmd = app.Command("clusters", "List the clusters of the organization and the ZeebeClusters that manage them.").Alias("ls")
+
+		importCmd             = app.Command("import", "Print ZeebeClusters that manage the named clusters, or all unmanaged clusters if none are named.")
+		importNames           = importCmd.Arg("cluster", "Name of a cluster to import.").Strings()
+		importSecretNamespace = importCmd.Flag("connection-secret-namespace", "Namespace to write connection secrets to. None are written if empty.").Default("crossplane-system").String()
+		importDeletionPolicy  = importCmd.Flag("deletion-policy", "Whether deleting a ZeebeCluster deletes its cluster.").Default(string(xpv1.DeletionOrphan)).Enum(string(xpv1.DeletionOrphan), string(xpv1.DeletionDelete))
+		importObserveOnly     = importCmd.Flag("observe-only", "Import clusters as observe-only ZeebeClusters, which never change or delete them.").Bool()
+
+		catalogCmd = app.Command("catalog", "List the plans, channels, generations and regions that clusters may be created with.")
+	)
+	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	zl := zap.New(zap.UseDevMode(*debug), zap.WriteTo(os.Stderr))
+	log := logging.NewNopLogger()
+	if *debug {
+		log = logging.NewLogrLogger(zl.WithName("kubectl-camunda"))
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = *kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: *kubeContext}).ClientConfig()
+	kingpin.FatalIfError(err, "Cannot load kubeconfig")
+
+	s := runtime.NewScheme()
+	kingpin.FatalIfError(clientgoscheme.AddToScheme(s), "Cannot add Kubernetes APIs to scheme")
+	kingpin.FatalIfError(apis.AddToScheme(s), "Cannot add Camunda Cloud APIs to scheme")
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	kingpin.FatalIfError(err, "Cannot create Kubernetes client")
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	svc, org, err := zeebecluster.Login(ctx, kube, *providerConfig, log)
+	kingpin.FatalIfError(err, "Cannot log in to Camunda Cloud with ProviderConfig %q", *providerConfig)
+	log.Debug("Logged in to Camunda Cloud", "organization", org, "provider-config", *providerConfig)
+
+	switch cmd {
+	case clustersCmd.FullCommand():
+		cls, err := plugin.ListClusters(ctx, svc, kube)
+		kingpin.FatalIfError(err, "Cannot list clusters")
+		kingpin.FatalIfError(plugin.PrintClusters(os.Stdout, cls), "Cannot print clusters")
+
+	case importCmd.FullCommand():
+		cls, err := plugin.ListClusters(ctx, svc, kube)
+		kingpin.FatalIfError(err, "Cannot list clusters")
+		zbs, skipped, err := plugin.Import(cls, *importNames, plugin.ImportOptions{
+			ProviderConfig:            *providerConfig,
+			ConnectionSecretNamespace: *importSecretNamespace,
+			DeletionPolicy:            xpv1.DeletionPolicy(*importDeletionPolicy),
+			ObserveOnly:               *importObserveOnly,
+		})
+		kingpin.FatalIfError(err, "Cannot import clusters")
+		for _, name := range skipped {
+			fmt.Fprintf(os.Stderr, "Skipping cluster %q: its name is not a valid object name.\n", name)
+		}
+		if len(zbs) == 0 {
+			fmt.Fprintln(os.Stderr, "No clusters to import.")
+			return
+		}
+		kingpin.FatalIfError(plugin.PrintZeebeClusters(os.Stdout, zbs), "Cannot print ZeebeClusters")
+
+	case catalogCmd.FullCommand():
+		p, err := plugin.GetCatalog(ctx, svc)
+		kingpin.FatalIfError(err, "Cannot get catalog")
+		kingpin.FatalIfError(plugin.PrintCatalog(os.Stdout, p), "Cannot print catalog")
+	}
+}
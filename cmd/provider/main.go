@@ -17,8 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"gopkg.in/alecthomas/kingpin.v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -29,16 +34,39 @@ import (
 
 	"github.com/salaboy/provider-camunda-cloud/apis"
 	"github.com/salaboy/provider-camunda-cloud/internal/controller"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/options"
+	"github.com/salaboy/provider-camunda-cloud/internal/tracing"
 )
 
+// tracingShutdownTimeout bounds how long buffered spans are flushed for when
+// the provider exits.
+const tracingShutdownTimeout = 5 * time.Second
+
 func main() {
 	var (
 		app            = kingpin.New(filepath.Base(os.Args[0]), "Template support for Crossplane.").DefaultEnvars()
 		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
 		syncPeriod     = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
 		leaderElection = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+
+		maxReconcileRate        = app.Flag("max-reconcile-rate", "Average number of reconciles per second of all controllers together. Bursts of ten times as many are allowed.").Default(strconv.Itoa(ratelimiter.DefaultProviderRPS)).Int()
+		pollInterval            = app.Flag("poll-interval", "How often a managed resource is observed while it is up to date, to detect drift.").Default(options.DefaultPollInterval.String()).Duration()
+		maxConcurrentReconciles = app.Flag("max-concurrent-reconciles", "Number of resources of a kind each controller reconciles at once.").Default(strconv.Itoa(options.DefaultMaxConcurrentReconciles)).Int()
+
+		enableWebhooks = app.Flag("enable-webhooks", "Serve admission webhooks that validate managed resources.").Default("false").Bool()
+		webhookPort    = app.Flag("webhook-port", "Port to serve admission webhooks on.").Default("9443").Int()
+		webhookCertDir = app.Flag("webhook-cert-dir", "Directory containing the tls.crt and tls.key to serve admission webhooks with.").Default("/tmp/k8s-webhook-server/serving-certs").String()
+
+		tracingExporter      = app.Flag("tracing-exporter", "Exporter to send traces with: "+strings.Join(tracing.Exporters(), ", ")+".").Default(tracing.ExporterNone).Enum(tracing.Exporters()...)
+		tracingEndpoint      = app.Flag("tracing-endpoint", "Endpoint to send traces to. Defaults to the local default of the exporter.").String()
+		tracingInsecure      = app.Flag("tracing-insecure", "Send OTLP traces without TLS.").Bool()
+		tracingSamplingRatio = app.Flag("tracing-sampling-ratio", "Fraction of reconciles to trace, between 0 and 1.").Default("1").Float64()
+		tracingAttributes    = app.Flag("tracing-attribute", "Resource attribute to report traces with, as key=value. May be repeated.").StringMap()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
+	if *maxReconcileRate < 1 || *maxConcurrentReconciles < 1 || *pollInterval <= 0 {
+		kingpin.Fatalf("--max-reconcile-rate, --max-concurrent-reconciles and --poll-interval must be positive")
+	}
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-template"))
@@ -49,7 +77,17 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "poll-interval", pollInterval.String(),
+		"max-reconcile-rate", *maxReconcileRate, "max-concurrent-reconciles", *maxConcurrentReconciles)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:      *tracingExporter,
+		Endpoint:      *tracingEndpoint,
+		Insecure:      *tracingInsecure,
+		SamplingRatio: *tracingSamplingRatio,
+		Attributes:    *tracingAttributes,
+	})
+	kingpin.FatalIfError(err, "Cannot setup tracing")
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
@@ -58,11 +96,27 @@ func main() {
 		LeaderElection:   *leaderElection,
 		LeaderElectionID: "crossplane-leader-election-provider-template",
 		SyncPeriod:       syncPeriod,
+		Port:             *webhookPort,
+		CertDir:          *webhookCertDir,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
-	rl := ratelimiter.NewDefaultProviderRateLimiter(ratelimiter.DefaultProviderRPS)
+	o := options.Options{
+		GlobalRateLimiter:       ratelimiter.NewDefaultProviderRateLimiter(*maxReconcileRate),
+		PollInterval:            *pollInterval,
+		MaxConcurrentReconciles: *maxConcurrentReconciles,
+	}
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Template APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log, rl), "Cannot setup Template controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	kingpin.FatalIfError(controller.Setup(mgr, log, o), "Cannot setup Template controllers")
+	if *enableWebhooks {
+		kingpin.FatalIfError(controller.SetupWebhooks(mgr, log), "Cannot setup Template webhooks")
+	}
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Info("Cannot flush traces", "error", err)
+	}
+	kingpin.FatalIfError(err, "Cannot start controller manager")
 }
@@ -0,0 +1,478 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultLoginURL is the OAuth token endpoint of Camunda Cloud.
+	DefaultLoginURL = "https://login.cloud.camunda.io/oauth/token"
+
+	// DefaultAPIURL is the base URL of the Camunda Cloud Console API.
+	DefaultAPIURL = "https://api.cloud.camunda.io"
+
+	// ClusterStatusNotFound is reported as the readiness of a cluster that
+	// the Console API does not know about.
+	ClusterStatusNotFound = "Not Found"
+
+	tracerName = "provider-camunda-cloud"
+)
+
+const (
+	errBuildRequest    = "cannot build request"
+	errSendRequest     = "cannot send request"
+	errDecodeResponse  = "cannot decode response"
+	errClusterExists   = "Cluster name already exists on Camunda Cloud"
+	errNoRegion        = "no region found with name"
+	errNoClusterID     = "cluster id should not be empty"
+	errLoginNoToken    = "login response did not contain an access token"
+	errGetClusterParam = "cannot get cluster parameters"
+	errNoChannel       = "no channel found with name"
+	errNoGeneration    = "no generation found with name"
+	errNoPlan          = "no cluster plan found with name"
+)
+
+// An Option modifies a Client.
+type Option func(c *Client)
+
+// WithHTTPClient configures the HTTP client used to reach Camunda Cloud.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithEndpoints configures the URLs of the OAuth token endpoint and the
+// Console API. Empty URLs are left at their defaults.
+func WithEndpoints(loginURL, apiURL string) Option {
+	return func(c *Client) {
+		if loginURL != "" {
+			c.loginURL = loginURL
+		}
+		if apiURL != "" {
+			c.apiURL = strings.TrimSuffix(apiURL, "/")
+		}
+	}
+}
+
+// WithAccessToken configures the access token presented to the Console API,
+// for example one obtained through a token exchange, so that no login is
+// required.
+func WithAccessToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithThrottle configures the Throttle that paces requests and holds them
+// back while the Console API asks clients to back off.
+func WithThrottle(t *Throttle) Option {
+	return func(c *Client) {
+		c.throttle = t
+	}
+}
+
+// WithUnauthorizedHandler configures a function that is called whenever the
+// Console API rejects the client's access token, for example to drop it from
+// a cache.
+func WithUnauthorizedHandler(fn func()) Option {
+	return func(c *Client) {
+		c.unauthorized = fn
+	}
+}
+
+// A ClusterClient manages the clusters of a Camunda Cloud organization. It is
+// the subset of a Client's operations that the ZeebeCluster controller uses.
+type ClusterClient interface {
+	GetClusterByNameWithContext(ctx context.Context, name string) (cc.Cluster, error)
+	GetClusterDetailsWithContext(ctx context.Context, clusterID string) (ClusterDetails, error)
+	CreateClusterWithParamsAndContext(ctx context.Context, clusterName string, clusterPlanName string, channelName string, generationName string, clusterRegion string) (string, error)
+	DeleteClusterWithContext(ctx context.Context, clusterID string) (bool, error)
+}
+
+var _ ClusterClient = &Client{}
+
+// A Client talks to the Camunda Cloud Console API. It provides the subset of
+// cc.CCClient's operations that the provider uses, with the same signatures,
+// but sends every request through a configurable HTTP client.
+type Client struct {
+	http     *http.Client
+	loginURL string
+	apiURL   string
+	token    string
+	expiry   time.Time
+	throttle *Throttle
+	tracer   trace.Tracer
+
+	unauthorized func()
+}
+
+// New returns a Client configured by the supplied options.
+func New(o ...Option) *Client {
+	c := &Client{
+		http:     &http.Client{Transport: http.DefaultTransport},
+		loginURL: DefaultLoginURL,
+		apiURL:   DefaultAPIURL,
+		tracer:   otel.Tracer(tracerName),
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+type loginResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// An HTTPError is returned when the Console API responds with an unexpected
+// status code.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound returns true if the supplied error indicates that the requested
+// Console API resource does not exist.
+func IsNotFound(err error) bool {
+	var he *HTTPError
+	return errors.As(err, &he) && he.StatusCode == http.StatusNotFound
+}
+
+// do sends a request of the supplied operation to the supplied URL and decodes
+// a successful JSON response into out, if out is not nil.
+func (c *Client) do(ctx context.Context, operation, method, url string, in, out interface{}, auth bool) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, errBuildRequest)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return errors.Wrap(err, errBuildRequest)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if c.throttle != nil {
+		if err := c.throttle.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		observeRequest(operation, 0, time.Since(start))
+		return errors.Wrap(err, errSendRequest)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	observeRequest(operation, resp.StatusCode, time.Since(start))
+
+	b, _ := ioutil.ReadAll(resp.Body)
+	if isThrottling(resp.StatusCode) {
+		now := time.Now()
+		d := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+		at := now.Add(d)
+		if c.throttle != nil {
+			at = c.throttle.Backoff(resp.StatusCode, d)
+		}
+		return &ThrottledError{StatusCode: resp.StatusCode, RetryAfter: d, RetryAt: at}
+	}
+	if resp.StatusCode == http.StatusUnauthorized && auth && c.unauthorized != nil {
+		c.unauthorized()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &HTTPError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(b))}
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(b, out), errDecodeResponse)
+}
+
+// LoginWithContext exchanges the supplied Console API client credentials for
+// an access token that is used by subsequent calls.
+func (c *Client) LoginWithContext(ctx context.Context, clientID string, clientSecret string) (bool, error) {
+	ctx, span := c.tracer.Start(ctx, "login")
+	defer span.End()
+
+	r := loginResponse{}
+	if err := c.do(ctx, OperationLogin, http.MethodPost, c.loginURL, cc.NewAuthRequestPayload(clientID, clientSecret), &r, false); err != nil {
+		observeLoginFailure(OperationLogin)
+		return false, err
+	}
+	if r.AccessToken == "" {
+		observeLoginFailure(OperationLogin)
+		return false, errors.New(errLoginNoToken)
+	}
+	c.token = r.AccessToken
+	c.expiry = time.Time{}
+	if r.ExpiresIn > 0 {
+		c.expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return true, nil
+}
+
+// AccessToken returns the client's access token and when it expires. The
+// expiry is zero if it is unknown.
+func (c *Client) AccessToken() (string, time.Time) {
+	if !c.expiry.IsZero() {
+		return c.token, c.expiry
+	}
+	claims, err := c.claims()
+	if err != nil {
+		return c.token, time.Time{}
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return c.token, time.Time{}
+	}
+	return c.token, time.Unix(int64(exp), 0)
+}
+
+// GetClusterParamsWithContext returns the channels, generations, plans and
+// regions that clusters may be created with.
+func (c *Client) GetClusterParamsWithContext(ctx context.Context) (*cc.ClusterParams, error) {
+	ctx, span := c.tracer.Start(ctx, "getClusterParams")
+	defer span.End()
+
+	p := &cc.ClusterParams{}
+	return p, c.do(ctx, OperationGetClusterParams, http.MethodGet, c.apiURL+"/clusters/parameters", nil, p, true)
+}
+
+// GetClustersWithContext returns all clusters of the organization.
+func (c *Client) GetClustersWithContext(ctx context.Context) ([]cc.Cluster, error) {
+	ctx, span := c.tracer.Start(ctx, "getClusters")
+	defer span.End()
+
+	l := []cc.Cluster{}
+	return l, c.do(ctx, OperationGetClusters, http.MethodGet, c.apiURL+"/clusters", nil, &l, true)
+}
+
+// GetClusterByNameWithContext returns the cluster with the supplied name. A
+// zero value cluster is returned if no such cluster exists.
+func (c *Client) GetClusterByNameWithContext(ctx context.Context, name string) (cc.Cluster, error) {
+	ctx, span := c.tracer.Start(ctx, "getClusterByName")
+	defer span.End()
+
+	l, err := c.GetClustersWithContext(ctx)
+	if err != nil {
+		return cc.Cluster{}, err
+	}
+	for _, cl := range l {
+		if cl.Name == name {
+			return cl, nil
+		}
+	}
+	return cc.Cluster{}, nil
+}
+
+// ClusterStatus is the status of a cluster as reported by the Console API. It
+// extends cc.ClusterStatus with the components that it does not know about.
+type ClusterStatus struct {
+	cc.ClusterStatus
+
+	OptimizeStatus string `json:"optimizeStatus"`
+	OptimizeURL    string `json:"optimizeUrl"`
+}
+
+// ClusterLinks are the addresses of a cluster's components.
+type ClusterLinks struct {
+	Zeebe    string `json:"zeebe"`
+	Operate  string `json:"operate"`
+	Tasklist string `json:"tasklist"`
+	Optimize string `json:"optimize"`
+}
+
+// A ClusterOwner is the Camunda Cloud user who created a cluster.
+type ClusterOwner struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ClusterDetails are the details of a cluster as reported by the Console API.
+type ClusterDetails struct {
+	cc.Cluster
+
+	Status      ClusterStatus `json:"status"`
+	Links       ClusterLinks  `json:"links"`
+	LastUpgrade string        `json:"lastUpgrade"`
+	Owner       ClusterOwner  `json:"owner"`
+}
+
+// GetClusterDetailsWithContext returns the details of the supplied cluster.
+// Details whose status is ClusterStatusNotFound are returned if no such
+// cluster exists.
+func (c *Client) GetClusterDetailsWithContext(ctx context.Context, clusterID string) (ClusterDetails, error) {
+	ctx, span := c.tracer.Start(ctx, "getClusterDetails")
+	defer span.End()
+
+	if clusterID == "" {
+		return ClusterDetails{}, errors.New(errNoClusterID)
+	}
+
+	d := ClusterDetails{}
+	err := c.do(ctx, OperationGetCluster, http.MethodGet, c.apiURL+"/clusters/"+clusterID, nil, &d, true)
+	if IsNotFound(err) {
+		return ClusterDetails{Status: ClusterStatus{ClusterStatus: cc.ClusterStatus{Ready: ClusterStatusNotFound}}}, nil
+	}
+	return d, err
+}
+
+// CreateClusterWithParamsAndContext creates a cluster from the supplied plan,
+// channel, generation and region, each identified by its ID or name, falling
+// back to the Console's defaults for any that are empty. It returns the new cluster's ID.
+func (c *Client) CreateClusterWithParamsAndContext(ctx context.Context, clusterName string, clusterPlanName string, channelName string, generationName string, clusterRegion string) (string, error) {
+	ctx, span := c.tracer.Start(ctx, "createClusterWithParams")
+	defer span.End()
+
+	existing, err := c.GetClusterByNameWithContext(ctx, clusterName)
+	if err != nil {
+		return "", err
+	}
+	if existing.ID != "" {
+		return "", errors.New(errClusterExists)
+	}
+
+	p, err := c.GetClusterParamsWithContext(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, errGetClusterParam)
+	}
+
+	region := FindRegion(p, clusterRegion)
+	if region.Id == "" {
+		return "", errors.Errorf("%s: %s", errNoRegion, clusterRegion)
+	}
+	channel := FindChannel(p, channelName)
+	if channel.Id == "" {
+		return "", errors.Errorf("%s: %s", errNoChannel, channelName)
+	}
+	generation := channel.DefaultGeneration
+	if generationName != "" {
+		generation = FindGeneration(channel, generationName)
+	}
+	if generation.Id == "" {
+		return "", errors.Errorf("%s: %s", errNoGeneration, generationName)
+	}
+	plan := FindPlan(p, clusterPlanName)
+	if plan.Id == "" {
+		return "", errors.Errorf("%s: %s", errNoPlan, clusterPlanName)
+	}
+
+	r := cc.ClusterCreatedResponse{}
+	in := cc.NewClusterCreationParams(clusterName, channel.Id, generation.Id, region.Id, plan.Id)
+	if err := c.do(ctx, OperationCreateCluster, http.MethodPost, c.apiURL+"/clusters", in, &r, true); err != nil {
+		return "", err
+	}
+	return r.ClusterId, nil
+}
+
+// DeleteClusterWithContext deletes the supplied cluster.
+func (c *Client) DeleteClusterWithContext(ctx context.Context, clusterID string) (bool, error) {
+	ctx, span := c.tracer.Start(ctx, "deleteCluster")
+	defer span.End()
+
+	if clusterID == "" {
+		return false, errors.New(errNoClusterID)
+	}
+	if err := c.do(ctx, OperationDeleteCluster, http.MethodDelete, c.apiURL+"/clusters/"+clusterID, nil, nil, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindRegion returns the region with the supplied ID or name, or Europe West
+// 1D if none is supplied.
+func FindRegion(p *cc.ClusterParams, name string) cc.Region {
+	if name == "" {
+		name = "Europe West 1D"
+	}
+	for _, r := range p.Regions {
+		if r.Id == name || r.Name == name {
+			return r
+		}
+	}
+	return cc.Region{}
+}
+
+// FindChannel returns the channel with the supplied ID or a name that contains
+// the supplied name, or the default channel if none is supplied.
+func FindChannel(p *cc.ClusterParams, name string) cc.Channel {
+	for _, ch := range p.Channels {
+		if (name == "" && ch.IsDefault) || (name != "" && (ch.Id == name || strings.Contains(ch.Name, name))) {
+			return ch
+		}
+	}
+	return cc.Channel{}
+}
+
+// FindGeneration returns the generation with the supplied ID or name among
+// those the supplied channel allows.
+func FindGeneration(ch cc.Channel, name string) cc.Generation {
+	for _, g := range ch.AllowedGeneration {
+		if g.Id == name || g.Name == name {
+			return g
+		}
+	}
+	return cc.Generation{}
+}
+
+// FindPlan returns the plan with the supplied ID or name, or the Development
+// plan if none is supplied.
+func FindPlan(p *cc.ClusterParams, name string) cc.ClusterPlantType {
+	if name == "" {
+		name = "Development"
+	}
+	for _, pt := range p.ClusterPlanTypes {
+		if pt.Id == name || pt.Name == name {
+			return pt
+		}
+	}
+	return cc.ClusterPlantType{}
+}
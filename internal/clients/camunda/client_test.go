@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestGetClusterDetails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/clusters/cool" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"uuid": "cool",
+			"name": "cool-cluster",
+			"created": "2021-03-01T12:00:00Z",
+			"lastUpgrade": "2021-04-01T12:00:00Z",
+			"planType": {"uuid": "p1", "name": "Development"},
+			"channel": {"uuid": "c1", "name": "Stable"},
+			"generation": {"uuid": "g1", "name": "Zeebe 1.0.0"},
+			"k8sContext": {"uuid": "r1", "name": "Europe West 1D"},
+			"links": {"zeebe": "cool.bru-2.zeebe.camunda.io:443", "operate": "https://operate.example.org"},
+			"owner": {"id": "u1", "name": "Jane Doe"},
+			"status": {
+				"ready": "Healthy",
+				"zeebeStatus": "Healthy",
+				"operateStatus": "Unhealthy",
+				"tasklistStatus": "Healthy",
+				"optimizeStatus": "Creating",
+				"zeebeUrl": "https://zeebe.example.org",
+				"optimizeUrl": "https://optimize.example.org"
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	type want struct {
+		details ClusterDetails
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		id     string
+		want   want
+	}{
+		"Found": {
+			reason: "The details of the cluster and the status of every component, including Optimize, should be returned.",
+			id:     "cool",
+			want: want{details: ClusterDetails{
+				Cluster: cc.Cluster{
+					ID:               "cool",
+					Name:             "cool-cluster",
+					Created:          "2021-03-01T12:00:00Z",
+					ClusterPlantType: cc.ClusterPlantType{Id: "p1", Name: "Development"},
+					Channel:          cc.Channel{Id: "c1", Name: "Stable"},
+					Generation:       cc.Generation{Id: "g1", Name: "Zeebe 1.0.0"},
+					K8sContext:       cc.K8sContext{ID: "r1", Name: "Europe West 1D"},
+				},
+				Status: ClusterStatus{
+					ClusterStatus: cc.ClusterStatus{
+						Ready:          "Healthy",
+						ZeebeStatus:    "Healthy",
+						OperateStatus:  "Unhealthy",
+						TaskListStatus: "Healthy",
+						ZeebeURL:       "https://zeebe.example.org",
+					},
+					OptimizeStatus: "Creating",
+					OptimizeURL:    "https://optimize.example.org",
+				},
+				Links:       ClusterLinks{Zeebe: "cool.bru-2.zeebe.camunda.io:443", Operate: "https://operate.example.org"},
+				LastUpgrade: "2021-04-01T12:00:00Z",
+				Owner:       ClusterOwner{ID: "u1", Name: "Jane Doe"},
+			}},
+		},
+		"NotFound": {
+			reason: "A cluster the Console API does not know about should be reported as not found.",
+			id:     "gone",
+			want:   want{details: ClusterDetails{Status: ClusterStatus{ClusterStatus: cc.ClusterStatus{Ready: ClusterStatusNotFound}}}},
+		},
+		"NoID": {
+			reason: "An empty cluster ID should be rejected without sending a request.",
+			want:   want{err: errors.New(errNoClusterID)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := New(WithHTTPClient(srv.Client()))
+			c.apiURL = srv.URL
+
+			got, err := c.GetClusterDetailsWithContext(context.Background(), tc.id)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetClusterDetailsWithContext(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.details, got); diff != "" {
+				t.Errorf("\n%s\nGetClusterDetailsWithContext(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides fake Camunda Cloud clients for testing.
+package fake
+
+import (
+	"context"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+var _ camunda.ClusterClient = &MockClusterClient{}
+
+// MockClusterClient is a mock camunda.ClusterClient whose behaviour is
+// provided by its functions. Calling a function that is not set panics.
+type MockClusterClient struct {
+	MockGetClusterByName    func(ctx context.Context, name string) (cc.Cluster, error)
+	MockGetClusterDetails   func(ctx context.Context, clusterID string) (camunda.ClusterDetails, error)
+	MockCreateClusterParams func(ctx context.Context, clusterName string, clusterPlanName string, channelName string, generationName string, clusterRegion string) (string, error)
+	MockDeleteCluster       func(ctx context.Context, clusterID string) (bool, error)
+}
+
+// GetClusterByNameWithContext calls MockGetClusterByName.
+func (m *MockClusterClient) GetClusterByNameWithContext(ctx context.Context, name string) (cc.Cluster, error) {
+	return m.MockGetClusterByName(ctx, name)
+}
+
+// GetClusterDetailsWithContext calls MockGetClusterDetails.
+func (m *MockClusterClient) GetClusterDetailsWithContext(ctx context.Context, clusterID string) (camunda.ClusterDetails, error) {
+	return m.MockGetClusterDetails(ctx, clusterID)
+}
+
+// CreateClusterWithParamsAndContext calls MockCreateClusterParams.
+func (m *MockClusterClient) CreateClusterWithParamsAndContext(ctx context.Context, clusterName string, clusterPlanName string, channelName string, generationName string, clusterRegion string) (string, error) {
+	return m.MockCreateClusterParams(ctx, clusterName, clusterPlanName, channelName, generationName, clusterRegion)
+}
+
+// DeleteClusterWithContext calls MockDeleteCluster.
+func (m *MockClusterClient) DeleteClusterWithContext(ctx context.Context, clusterID string) (bool, error) {
+	return m.MockDeleteCluster(ctx, clusterID)
+}
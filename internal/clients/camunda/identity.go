@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+)
+
+const (
+	errNoTokenExchange    = "tokenExchange must be configured when using InjectedIdentity credentials"
+	errNoEndpoint         = "tokenExchange endpoint must not be empty"
+	errReadSubjectToken   = "cannot read projected service account token"
+	errDecodeSubjectToken = "cannot decode projected service account token"
+	errSubjectAudience    = "projected service account token must have the audience %q, got %q"
+	errBuildExchangeReq   = "cannot build token exchange request"
+	errExchangeToken      = "cannot exchange service account token"
+	errDecodeExchangeRes  = "cannot decode token exchange response"
+	errNoAccessToken      = "token exchange response did not contain an access token"
+)
+
+const (
+	// DefaultAudience is the audience of Camunda Console API access tokens.
+	DefaultAudience = "api.cloud.camunda.io"
+
+	// SubjectTokenPath is where the provider reads the service account token
+	// it exchanges. Kubernetes projects it there with the audience
+	// SubjectTokenAudience, so the provider never presents its API server
+	// token to a token-exchange endpoint.
+	SubjectTokenPath = "/var/run/secrets/camunda.crossplane.io/serviceaccount/token"
+
+	// SubjectTokenAudience is the audience the projected service account
+	// token must have.
+	SubjectTokenAudience = "camunda.crossplane.io"
+
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeJWT           = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// subjectTokenPath is a variable so that tests can project their own token.
+var subjectTokenPath = SubjectTokenPath
+
+type tokenExchangeResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// ExchangeToken exchanges the projected service account token described by the
+// supplied configuration for a Camunda access token, per RFC 8693.
+func ExchangeToken(ctx context.Context, hc *http.Client, te *v1alpha1.TokenExchange) (string, error) {
+	token, err := exchangeToken(ctx, hc, te)
+	if err != nil {
+		observeLoginFailure(OperationTokenExchange)
+	}
+	return token, err
+}
+
+func exchangeToken(ctx context.Context, hc *http.Client, te *v1alpha1.TokenExchange) (string, error) {
+	if te == nil {
+		return "", errors.New(errNoTokenExchange)
+	}
+	if te.Endpoint == "" {
+		return "", errors.New(errNoEndpoint)
+	}
+
+	b, err := ioutil.ReadFile(subjectTokenPath)
+	if err != nil {
+		return "", errors.Wrap(err, errReadSubjectToken)
+	}
+	subject := strings.TrimSpace(string(b))
+	aud, err := audiences(subject)
+	if err != nil {
+		return "", err
+	}
+	if !contains(aud, SubjectTokenAudience) {
+		return "", errors.Errorf(errSubjectAudience, SubjectTokenAudience, strings.Join(aud, ","))
+	}
+
+	audience := te.Audience
+	if audience == "" {
+		audience = DefaultAudience
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", grantTypeTokenExchange)
+	form.Set("subject_token", subject)
+	form.Set("subject_token_type", tokenTypeJWT)
+	form.Set("requested_token_type", tokenTypeAccessToken)
+	form.Set("audience", audience)
+	if te.ClientID != "" {
+		form.Set("client_id", te.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, te.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, errBuildExchangeReq)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	start := time.Now()
+	resp, err := hc.Do(req)
+	if err != nil {
+		observeRequest(OperationTokenExchange, 0, time.Since(start))
+		return "", errors.Wrap(err, errExchangeToken)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	observeRequest(OperationTokenExchange, resp.StatusCode, time.Since(start))
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("%s: HTTP %d: %s", errExchangeToken, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	r := tokenExchangeResponse{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", errors.Wrap(err, errDecodeExchangeRes)
+	}
+	if r.AccessToken == "" {
+		return "", errors.New(errNoAccessToken)
+	}
+	return r.AccessToken, nil
+}
+
+// audiences returns the audiences of the supplied JWT. The provider only reads
+// them to decide whether to present the token, so its signature is not
+// verified.
+func audiences(token string) ([]string, error) {
+	claims, err := decodeClaims(token)
+	if err != nil {
+		return nil, errors.Wrap(err, errDecodeSubjectToken)
+	}
+
+	// The aud claim is optional, and either a single audience or a list of
+	// them.
+	switch aud := claims["aud"].(type) {
+	case string:
+		return []string{aud}, nil
+	case []interface{}:
+		out := make([]string, 0, len(aud))
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out, nil
+	}
+	return nil, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+)
+
+func TestExchangeToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+	defer func(p string) { subjectTokenPath = p }(subjectTokenPath)
+
+	token := jwt(`{"aud":"camunda.crossplane.io"}`)
+	other := jwt(`{"aud":["sts.example.org","camunda.crossplane.io"]}`)
+	apiServer := jwt(`{"aud":["https://kubernetes.default.svc.cluster.local"]}`)
+	noAudience := jwt(`{"sub":"system:serviceaccount:crossplane-system:provider"}`)
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tokenPath := write("token", token)
+	otherPath := write("other", other)
+	apiServerPath := write("api-server", apiServer)
+	noAudiencePath := write("no-audience", noAudience)
+	opaquePath := write("opaque", "sa-token")
+	missingPath := filepath.Join(dir, "missing")
+	_, errMissing := ioutil.ReadFile(missingPath)
+
+	sent := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get("grant_type") != grantTypeTokenExchange || r.Form.Get("subject_token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("invalid_grant"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"camunda-token"}`))
+	}))
+	defer srv.Close()
+
+	type want struct {
+		token string
+		sent  bool
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		path   string
+		te     *v1alpha1.TokenExchange
+		want   want
+	}{
+		"NotConfigured": {
+			reason: "An error should be returned if no token exchange is configured.",
+			path:   tokenPath,
+			want:   want{err: errors.New(errNoTokenExchange)},
+		},
+		"NoEndpoint": {
+			reason: "An error should be returned if no endpoint is configured.",
+			path:   tokenPath,
+			te:     &v1alpha1.TokenExchange{},
+			want:   want{err: errors.New(errNoEndpoint)},
+		},
+		"NoSubjectToken": {
+			reason: "An error should be returned rather than falling back to another token if no token is projected.",
+			path:   missingPath,
+			te:     &v1alpha1.TokenExchange{Endpoint: srv.URL},
+			want:   want{err: errors.Wrap(errMissing, errReadSubjectToken)},
+		},
+		"NotAJWT": {
+			reason: "A projected token that is not a JWT should not be presented.",
+			path:   opaquePath,
+			te:     &v1alpha1.TokenExchange{Endpoint: srv.URL},
+			want:   want{err: errors.Wrap(errors.New(errNotJWT), errDecodeSubjectToken)},
+		},
+		"APIServerAudience": {
+			reason: "A token for the API server should not be presented.",
+			path:   apiServerPath,
+			te:     &v1alpha1.TokenExchange{Endpoint: srv.URL},
+			want:   want{err: errors.Errorf(errSubjectAudience, SubjectTokenAudience, "https://kubernetes.default.svc.cluster.local")},
+		},
+		"NoAudience": {
+			reason: "A token without an audience should not be presented.",
+			path:   noAudiencePath,
+			te:     &v1alpha1.TokenExchange{Endpoint: srv.URL},
+			want:   want{err: errors.Errorf(errSubjectAudience, SubjectTokenAudience, "")},
+		},
+		"Rejected": {
+			reason: "An error should be returned if the endpoint rejects the subject token.",
+			path:   otherPath,
+			te:     &v1alpha1.TokenExchange{Endpoint: srv.URL},
+			want:   want{sent: true, err: errors.Errorf("%s: HTTP %d: %s", errExchangeToken, http.StatusUnauthorized, "invalid_grant")},
+		},
+		"Success": {
+			reason: "The exchanged access token should be returned.",
+			path:   tokenPath,
+			te:     &v1alpha1.TokenExchange{Endpoint: srv.URL},
+			want:   want{token: "camunda-token", sent: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			subjectTokenPath, sent = tc.path, false
+			got, err := ExchangeToken(context.Background(), srv.Client(), tc.te)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExchangeToken(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, got); diff != "" {
+				t.Errorf("\n%s\nExchangeToken(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.sent, sent); diff != "" {
+				t.Errorf("\n%s\nExchangeToken(...): -want token sent, +got token sent:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
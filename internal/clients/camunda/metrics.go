@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// MetricsNamespace prefixes the names of all metrics of the provider.
+const MetricsNamespace = "provider_camunda_cloud"
+
+// Operations reported in the operation label of Console API metrics.
+const (
+	OperationLogin            = "login"
+	OperationTokenExchange    = "token_exchange"
+	OperationGetClusterParams = "get_cluster_params"
+	OperationGetClusters      = "get_clusters"
+	OperationGetCluster       = "get_cluster"
+	OperationCreateCluster    = "create_cluster"
+	OperationDeleteCluster    = "delete_cluster"
+)
+
+// codeError is reported in the code label of requests that got no response.
+const codeError = "error"
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: "console_api",
+		Name:      "requests_total",
+		Help:      "Number of requests sent to the Camunda Cloud Console API, by operation and HTTP status code.",
+	}, []string{"operation", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: "console_api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests sent to the Camunda Cloud Console API, by operation and HTTP status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "code"})
+
+	loginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: "console_api",
+		Name:      "login_failures_total",
+		Help:      "Number of failed attempts to obtain a Camunda Cloud access token, by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requests, requestDuration, loginFailures)
+}
+
+// observeRequest records a Console API request of the supplied operation that
+// took the supplied time. A status code of zero means no response was received.
+func observeRequest(operation string, status int, d time.Duration) {
+	code := codeError
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	requests.WithLabelValues(operation, code).Inc()
+	requestDuration.WithLabelValues(operation, code).Observe(d.Seconds())
+}
+
+// observeLoginFailure records a failed attempt to obtain an access token.
+func observeLoginFailure(operation string) {
+	loginFailures.WithLabelValues(operation).Inc()
+}
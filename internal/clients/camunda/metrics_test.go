@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	c := New(WithHTTPClient(srv.Client()))
+	c.loginURL = srv.URL + "/oauth/token"
+	c.apiURL = srv.URL
+
+	gets := testutil.ToFloat64(requests.WithLabelValues(OperationGetClusters, "200"))
+	logins := testutil.ToFloat64(requests.WithLabelValues(OperationLogin, "401"))
+	failures := testutil.ToFloat64(loginFailures.WithLabelValues(OperationLogin))
+
+	if _, err := c.GetClustersWithContext(context.Background()); err != nil {
+		t.Fatalf("GetClustersWithContext(...): %v", err)
+	}
+	if _, err := c.LoginWithContext(context.Background(), "id", "secret"); err == nil {
+		t.Fatalf("LoginWithContext(...): want error, got nil")
+	}
+
+	if got := testutil.ToFloat64(requests.WithLabelValues(OperationGetClusters, "200")) - gets; got != 1 {
+		t.Errorf("requests{operation=%q, code=\"200\"}: want 1 more, got %v", OperationGetClusters, got)
+	}
+	if got := testutil.ToFloat64(requests.WithLabelValues(OperationLogin, "401")) - logins; got != 1 {
+		t.Errorf("requests{operation=%q, code=\"401\"}: want 1 more, got %v", OperationLogin, got)
+	}
+	if got := testutil.ToFloat64(loginFailures.WithLabelValues(OperationLogin)) - failures; got != 1 {
+		t.Errorf("loginFailures{operation=%q}: want 1 more, got %v", OperationLogin, got)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ClaimOrganizationID is the access token claim holding the Camunda
+	// Cloud organization the token was issued for.
+	ClaimOrganizationID = "https://camunda.com/orgId"
+
+	errNotJWT            = "token is not a JWT"
+	errDecodeClaims      = "cannot decode token claims"
+	errNoOrganizationID  = "access token does not name an organization"
+	errWrongOrganization = "credentials belong to organization %q, not %q"
+)
+
+// OrganizationID returns the Camunda Cloud organization that the client's
+// access token was issued for. The token's signature is not verified; it was
+// received directly from the login or token-exchange endpoint.
+func (c *Client) OrganizationID() (string, error) {
+	claims, err := c.claims()
+	if err != nil {
+		return "", err
+	}
+	org, _ := claims[ClaimOrganizationID].(string)
+	if org == "" {
+		return "", errors.New(errNoOrganizationID)
+	}
+	return org, nil
+}
+
+// claims decodes the claims of the client's access token.
+func (c *Client) claims() (map[string]interface{}, error) {
+	return decodeClaims(c.token)
+}
+
+// decodeClaims decodes the claims of the supplied JWT without verifying its
+// signature.
+func decodeClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New(errNotJWT)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, errors.Wrap(err, errDecodeClaims)
+	}
+	claims := map[string]interface{}{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, errors.Wrap(err, errDecodeClaims)
+	}
+	return claims, nil
+}
+
+// ValidateOrganization returns the organization of the client's access token.
+// It returns an error if want is not empty and the token was issued for a
+// different organization. Tokens that do not name an organization are only
+// accepted when want is empty.
+func (c *Client) ValidateOrganization(want string) (string, error) {
+	got, err := c.OrganizationID()
+	if want == "" {
+		return got, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if got != want {
+		return "", errors.Errorf(errWrongOrganization, got, want)
+	}
+	return got, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func jwt(claims string) string {
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".sig"
+}
+
+func TestValidateOrganization(t *testing.T) {
+	type want struct {
+		org string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		token  string
+		org    string
+		want   want
+	}{
+		"Match": {
+			reason: "The organization should be returned if it matches the expected one.",
+			token:  jwt(`{"https://camunda.com/orgId":"org-a"}`),
+			org:    "org-a",
+			want:   want{org: "org-a"},
+		},
+		"Mismatch": {
+			reason: "An error should be returned if the token belongs to another organization.",
+			token:  jwt(`{"https://camunda.com/orgId":"org-b"}`),
+			org:    "org-a",
+			want:   want{err: errors.Errorf(errWrongOrganization, "org-b", "org-a")},
+		},
+		"NotRequired": {
+			reason: "The token's organization should be returned if none is expected.",
+			token:  jwt(`{"https://camunda.com/orgId":"org-b"}`),
+			want:   want{org: "org-b"},
+		},
+		"OpaqueTokenNotRequired": {
+			reason: "Opaque tokens should be accepted if no organization is expected.",
+			token:  "opaque",
+		},
+		"OpaqueTokenRequired": {
+			reason: "Opaque tokens should be rejected if an organization is expected.",
+			token:  "opaque",
+			org:    "org-a",
+			want:   want{err: errors.New(errNotJWT)},
+		},
+		"NoClaimRequired": {
+			reason: "Tokens without an organization claim should be rejected if an organization is expected.",
+			token:  jwt(`{"sub":"client"}`),
+			org:    "org-a",
+			want:   want{err: errors.New(errNoOrganizationID)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := New(WithAccessToken(tc.token))
+			got, err := c.ValidateOrganization(tc.org)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.ValidateOrganization(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.org, got); diff != "" {
+				t.Errorf("\n%s\nc.ValidateOrganization(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+)
+
+const (
+	// DefaultRequestsPerMinute is the default sustained rate of Console API
+	// requests per ProviderConfig.
+	DefaultRequestsPerMinute = 60
+
+	// DefaultBurst is the default number of Console API requests per
+	// ProviderConfig that may be sent in a burst.
+	DefaultBurst = 10
+
+	// DefaultRetryAfter is how long requests are held back when the Console
+	// API throttles without saying for how long.
+	DefaultRetryAfter = 30 * time.Second
+)
+
+// A ThrottledError is returned when a request was not sent, or was rejected,
+// because the Console API asked its clients to back off.
+type ThrottledError struct {
+	// StatusCode of the response that asked clients to back off.
+	StatusCode int
+
+	// RetryAfter is how long to wait before sending another request.
+	RetryAfter time.Duration
+
+	// RetryAt is when another request may be sent.
+	RetryAt time.Time
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("Console API is throttling requests (HTTP %d), retry after %s", e.StatusCode, e.RetryAfter.Round(time.Second))
+}
+
+// IsThrottled returns true if the supplied error indicates that the Console API
+// is throttling requests.
+func IsThrottled(err error) bool {
+	var te *ThrottledError
+	return errors.As(err, &te)
+}
+
+// RetryAfter returns how long to wait before retrying the request that
+// produced the supplied error, if the Console API was throttling requests.
+func RetryAfter(err error) (time.Duration, bool) {
+	var te *ThrottledError
+	if !errors.As(err, &te) {
+		return 0, false
+	}
+	return te.RetryAfter, true
+}
+
+// RetryAt returns when the request that produced the supplied error may be
+// retried, if the Console API was throttling requests.
+func RetryAt(err error) (time.Time, bool) {
+	var te *ThrottledError
+	if !errors.As(err, &te) {
+		return time.Time{}, false
+	}
+	return te.RetryAt, true
+}
+
+// A Throttle limits the rate of requests sent on behalf of one ProviderConfig
+// using a token bucket, and holds requests back for as long as the Console API
+// asked its clients to back off.
+type Throttle struct {
+	limiter *rate.Limiter
+
+	mu     sync.Mutex
+	until  time.Time
+	status int
+}
+
+// NewThrottle returns a Throttle that allows the supplied sustained number of
+// requests per minute and burst.
+func NewThrottle(perMinute, burst int) *Throttle {
+	return &Throttle{limiter: rate.NewLimiter(rate.Limit(float64(perMinute)/60), burst)}
+}
+
+// Wait blocks until a request may be sent. It returns a ThrottledError without
+// blocking if the Console API asked clients to back off.
+func (t *Throttle) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	until := t.until
+	status := t.status
+	t.mu.Unlock()
+
+	if wait := time.Until(until); wait > 0 {
+		return &ThrottledError{StatusCode: status, RetryAfter: wait, RetryAt: until}
+	}
+	return t.limiter.Wait(ctx)
+}
+
+// Backoff holds requests back for the supplied duration. It returns when
+// requests may be sent again, which may be later than the supplied duration if
+// requests were already held back for longer.
+func (t *Throttle) Backoff(status int, d time.Duration) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if u := time.Now().Add(d); u.After(t.until) {
+		t.until = u
+		t.status = status
+	}
+	return t.until
+}
+
+func (t *Throttle) setRate(perMinute, burst int) {
+	l := rate.Limit(float64(perMinute) / 60)
+	if t.limiter.Limit() != l {
+		t.limiter.SetLimit(l)
+	}
+	if t.limiter.Burst() != burst {
+		t.limiter.SetBurst(burst)
+	}
+}
+
+// Throttles hands out one Throttle per ProviderConfig.
+type Throttles struct {
+	mu sync.Mutex
+	m  map[string]*Throttle
+}
+
+// NewThrottles returns an empty set of Throttles.
+func NewThrottles() *Throttles {
+	return &Throttles{m: map[string]*Throttle{}}
+}
+
+// Get returns the Throttle of the named ProviderConfig, configured with the
+// supplied rate limit or the defaults if it is nil.
+func (ts *Throttles) Get(name string, rl *v1alpha1.RateLimit) *Throttle {
+	perMinute, burst := DefaultRequestsPerMinute, DefaultBurst
+	if rl != nil {
+		if rl.RequestsPerMinute > 0 {
+			perMinute = rl.RequestsPerMinute
+		}
+		if rl.Burst > 0 {
+			burst = rl.Burst
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	t, ok := ts.m[name]
+	if !ok {
+		t = NewThrottle(perMinute, burst)
+		ts.m[name] = t
+		return t
+	}
+	t.setRate(perMinute, burst)
+	return t
+}
+
+// isThrottling returns true if the supplied status code asks clients to back
+// off.
+func isThrottling(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return DefaultRetryAfter
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return DefaultRetryAfter
+}
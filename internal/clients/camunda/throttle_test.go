@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		value  string
+		want   time.Duration
+	}{
+		"Empty": {
+			reason: "The default should be used if no header was sent.",
+			want:   DefaultRetryAfter,
+		},
+		"Seconds": {
+			reason: "A number of seconds should be honoured.",
+			value:  "120",
+			want:   2 * time.Minute,
+		},
+		"HTTPDate": {
+			reason: "An HTTP date should be honoured relative to now.",
+			value:  now.Add(90 * time.Second).Format(http.TimeFormat),
+			want:   90 * time.Second,
+		},
+		"PastHTTPDate": {
+			reason: "An HTTP date in the past should not hold requests back.",
+			value:  now.Add(-time.Minute).Format(http.TimeFormat),
+			want:   0,
+		},
+		"Garbage": {
+			reason: "The default should be used if the header cannot be parsed.",
+			value:  "soon",
+			want:   DefaultRetryAfter,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := parseRetryAfter(tc.value, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nparseRetryAfter(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClientHonoursRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := New(WithHTTPClient(srv.Client()), WithThrottle(NewThrottle(600, 10)))
+	c.apiURL = srv.URL
+
+	_, err := c.GetClustersWithContext(context.Background())
+	if d, ok := RetryAfter(err); !ok || d != time.Minute {
+		t.Fatalf("GetClustersWithContext(...): want throttled error retrying after 1m, got %v", err)
+	}
+
+	first, _ := RetryAt(err)
+
+	_, err = c.GetClustersWithContext(context.Background())
+	if !IsThrottled(err) {
+		t.Fatalf("GetClustersWithContext(...): want throttled error while backing off, got %v", err)
+	}
+	if at, _ := RetryAt(err); !at.Equal(first) {
+		t.Errorf("GetClustersWithContext(...): want retry at %s while backing off, got %s", first, at)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("GetClustersWithContext(...): want 1 request while backing off, got %d", got)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// tokenExpirySkew is how long before its expiry a cached token is no longer
+// handed out, so that it does not expire mid-reconcile.
+const tokenExpirySkew = time.Minute
+
+// A Fingerprint identifies the credentials an access token was issued for.
+type Fingerprint [sha256.Size]byte
+
+// FingerprintOf returns the fingerprint of the supplied credentials.
+func FingerprintOf(credentials []byte) Fingerprint {
+	return sha256.Sum256(credentials)
+}
+
+type cachedToken struct {
+	fingerprint Fingerprint
+	token       string
+	expiry      time.Time
+}
+
+// Tokens caches Console API access tokens per ProviderConfig, so that the
+// provider does not need to log in for every reconcile.
+type Tokens struct {
+	mu sync.Mutex
+	m  map[string]cachedToken
+}
+
+// NewTokens returns an empty token cache.
+func NewTokens() *Tokens {
+	return &Tokens{m: map[string]cachedToken{}}
+}
+
+// Get returns the cached access token of the named ProviderConfig, if it was
+// issued for credentials with the supplied fingerprint and is not about to
+// expire.
+func (t *Tokens) Get(name string, fp Fingerprint) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ct, ok := t.m[name]
+	if !ok || ct.fingerprint != fp || time.Now().Add(tokenExpirySkew).After(ct.expiry) {
+		return "", false
+	}
+	return ct.token, true
+}
+
+// Set caches the supplied access token of the named ProviderConfig. Tokens
+// without a known expiry are not cached.
+func (t *Tokens) Set(name string, fp Fingerprint, token string, expiry time.Time) {
+	if token == "" || expiry.IsZero() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.m[name] = cachedToken{fingerprint: fp, token: token, expiry: expiry}
+}
+
+// Invalidate drops the cached access token of the named ProviderConfig.
+func (t *Tokens) Invalidate(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.m, name)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokens(t *testing.T) {
+	const pc = "cool"
+	creds := FingerprintOf([]byte("cool-credentials"))
+	rotated := FingerprintOf([]byte("rotated-credentials"))
+
+	type token struct {
+		name   string
+		fp     Fingerprint
+		token  string
+		expiry time.Time
+	}
+
+	type want struct {
+		token string
+		ok    bool
+	}
+
+	cases := map[string]struct {
+		reason     string
+		set        *token
+		invalidate bool
+		fp         Fingerprint
+		want       want
+	}{
+		"NotCached": {
+			reason: "No token should be returned if none was cached.",
+			fp:     creds,
+		},
+		"Cached": {
+			reason: "A cached token that is not about to expire should be returned.",
+			set:    &token{name: pc, fp: creds, token: "cool-token", expiry: time.Now().Add(time.Hour)},
+			fp:     creds,
+			want:   want{token: "cool-token", ok: true},
+		},
+		"AboutToExpire": {
+			reason: "A cached token that expires within the skew should not be returned.",
+			set:    &token{name: pc, fp: creds, token: "cool-token", expiry: time.Now().Add(tokenExpirySkew / 2)},
+			fp:     creds,
+		},
+		"Expired": {
+			reason: "An expired token should not be returned.",
+			set:    &token{name: pc, fp: creds, token: "cool-token", expiry: time.Now().Add(-time.Minute)},
+			fp:     creds,
+		},
+		"NoExpiry": {
+			reason: "A token without a known expiry should not be cached.",
+			set:    &token{name: pc, fp: creds, token: "cool-token"},
+			fp:     creds,
+		},
+		"FingerprintMismatch": {
+			reason: "A token issued for other credentials should not be returned.",
+			set:    &token{name: pc, fp: creds, token: "cool-token", expiry: time.Now().Add(time.Hour)},
+			fp:     rotated,
+		},
+		"OtherProviderConfig": {
+			reason: "A token cached for another ProviderConfig should not be returned.",
+			set:    &token{name: "other", fp: creds, token: "other-token", expiry: time.Now().Add(time.Hour)},
+			fp:     creds,
+		},
+		"Invalidated": {
+			reason:     "An invalidated token should not be returned.",
+			set:        &token{name: pc, fp: creds, token: "cool-token", expiry: time.Now().Add(time.Hour)},
+			fp:         creds,
+			invalidate: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tokens := NewTokens()
+			if tc.set != nil {
+				tokens.Set(tc.set.name, tc.set.fp, tc.set.token, tc.set.expiry)
+			}
+			if tc.invalidate {
+				tokens.Invalidate(tc.set.name)
+			}
+			got, ok := tokens.Get(pc, tc.fp)
+			if diff := cmp.Diff(tc.want, want{token: got, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+)
+
+const (
+	errParseProxyURL  = "cannot parse proxy URL"
+	errGetCABundle    = "cannot get CA bundle secret"
+	errNoCABundleKey  = "CA bundle secret does not contain the referenced key"
+	errSystemCertPool = "cannot load system certificate pool"
+	errNoCertificates = "CA bundle does not contain any PEM encoded certificates"
+)
+
+// A cachedTransport is a transport and a digest of the settings it was built
+// from.
+type cachedTransport struct {
+	digest    [sha256.Size]byte
+	transport *http.Transport
+}
+
+// transports are cached per ProviderConfig, so that connections are reused
+// across reconciles rather than leaked per client. An entry is replaced when
+// its ProviderConfig's settings change.
+var transports = struct {
+	sync.Mutex
+	cache map[string]cachedTransport
+}{cache: map[string]cachedTransport{}}
+
+// NewHTTPClient returns an HTTP client for the named ProviderConfig whose
+// transport honours the supplied proxy and CA bundle settings. The default
+// transport, which honours the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables, is used when no settings are supplied.
+func NewHTTPClient(ctx context.Context, kube client.Client, name string, t *v1alpha1.Transport) (*http.Client, error) {
+	if t == nil || (t.ProxyURL == "" && t.CABundleSecretRef == nil) {
+		evictTransport(name)
+		return &http.Client{Transport: http.DefaultTransport}, nil
+	}
+
+	if t.ProxyURL != "" {
+		if _, err := url.Parse(t.ProxyURL); err != nil {
+			return nil, errors.Wrap(err, errParseProxyURL)
+		}
+	}
+
+	var bundle []byte
+	if ref := t.CABundleSecretRef; ref != nil {
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return nil, errors.Wrap(err, errGetCABundle)
+		}
+		b, ok := s.Data[ref.Key]
+		if !ok {
+			return nil, errors.New(errNoCABundleKey)
+		}
+		bundle = b
+	}
+
+	h := sha256.New()
+	_, _ = h.Write([]byte(t.ProxyURL + "\x00" + strings.Join(t.NoProxy, ",") + "\x00"))
+	_, _ = h.Write(bundle)
+	var digest [sha256.Size]byte
+	copy(digest[:], h.Sum(nil))
+
+	transports.Lock()
+	defer transports.Unlock()
+	cached, ok := transports.cache[name]
+	if ok && cached.digest == digest {
+		return &http.Client{Transport: cached.transport}, nil
+	}
+
+	tr, err := newTransport(t, bundle)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		cached.transport.CloseIdleConnections()
+	}
+	transports.cache[name] = cachedTransport{digest: digest, transport: tr}
+	return &http.Client{Transport: tr}, nil
+}
+
+// evictTransport drops the cached transport of the named ProviderConfig, if
+// any, closing its idle connections.
+func evictTransport(name string) {
+	transports.Lock()
+	defer transports.Unlock()
+	if cached, ok := transports.cache[name]; ok {
+		cached.transport.CloseIdleConnections()
+		delete(transports.cache, name)
+	}
+}
+
+func newTransport(t *v1alpha1.Transport, bundle []byte) (*http.Transport, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+
+	if t.ProxyURL != "" {
+		pc := &httpproxy.Config{
+			HTTPProxy:  t.ProxyURL,
+			HTTPSProxy: t.ProxyURL,
+			NoProxy:    strings.Join(t.NoProxy, ","),
+		}
+		pf := pc.ProxyFunc()
+		tr.Proxy = func(r *http.Request) (*url.URL, error) { return pf(r.URL) }
+	}
+
+	if len(bundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, errors.Wrap(err, errSystemCertPool)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errors.New(errNoCertificates)
+		}
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return tr, nil
+}
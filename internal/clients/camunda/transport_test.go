@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package camunda
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+)
+
+// withCABundle returns a MockGetFn that returns a Secret holding the supplied
+// bundle under the key "ca.crt".
+func withCABundle(bundle []byte) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		s := obj.(*corev1.Secret)
+		s.Data = map[string][]byte{"ca.crt": bundle}
+		return nil
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	ref := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "ca"}, Key: "ca.crt"}
+
+	type want struct {
+		trusted bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		t      *v1alpha1.Transport
+		want   want
+	}{
+		"Default": {
+			reason: "The default transport should be used if no settings are supplied.",
+		},
+		"GetSecretError": {
+			reason: "An error should be returned if the CA bundle Secret cannot be read.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			t:      &v1alpha1.Transport{CABundleSecretRef: ref},
+			want:   want{err: errors.Wrap(errBoom, errGetCABundle)},
+		},
+		"MissingKey": {
+			reason: "An error should be returned if the CA bundle Secret does not contain the referenced key.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			t:      &v1alpha1.Transport{CABundleSecretRef: ref},
+			want:   want{err: errors.New(errNoCABundleKey)},
+		},
+		"BadPEM": {
+			reason: "An error should be returned if the CA bundle does not contain any certificates.",
+			kube:   &test.MockClient{MockGet: withCABundle([]byte("not a certificate"))},
+			t:      &v1alpha1.Transport{CABundleSecretRef: ref},
+			want:   want{err: errors.New(errNoCertificates)},
+		},
+		"CABundle": {
+			reason: "Servers whose certificates are signed by the CA bundle should be trusted.",
+			kube:   &test.MockClient{MockGet: withCABundle(bundle)},
+			t:      &v1alpha1.Transport{CABundleSecretRef: ref},
+			want:   want{trusted: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hc, err := NewHTTPClient(context.Background(), tc.kube, name, tc.t)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNewHTTPClient(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			rsp, err := hc.Get(srv.URL)
+			if err == nil {
+				_ = rsp.Body.Close()
+			}
+			if diff := cmp.Diff(tc.want.trusted, err == nil); diff != "" {
+				t.Errorf("\n%s\nNewHTTPClient(...): -want trusted, +got trusted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	tr := &v1alpha1.Transport{
+		ProxyURL: "http://proxy.example.org:3128",
+		NoProxy:  []string{"internal.example.org", "10.0.0.0/8"},
+	}
+	hc, err := NewHTTPClient(context.Background(), nil, "proxy", tr)
+	if err != nil {
+		t.Fatalf("NewHTTPClient(...): %s", err)
+	}
+	proxy := hc.Transport.(*http.Transport).Proxy
+
+	cases := map[string]struct {
+		reason string
+		url    string
+		want   string
+	}{
+		"Proxied": {
+			reason: "Requests to hosts that are not excluded should be sent through the proxy.",
+			url:    "https://api.cloud.camunda.io/clusters",
+			want:   "http://proxy.example.org:3128",
+		},
+		"NoProxyHost": {
+			reason: "Requests to excluded hosts should be sent directly.",
+			url:    "https://internal.example.org/token",
+		},
+		"NoProxySubdomain": {
+			reason: "Requests to subdomains of excluded domains should be sent directly.",
+			url:    "https://login.internal.example.org/token",
+		},
+		"NoProxyCIDR": {
+			reason: "Requests to addresses in excluded CIDRs should be sent directly.",
+			url:    "https://10.1.2.3/token",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := proxy(r)
+			if err != nil {
+				t.Fatalf("Proxy(...): %s", err)
+			}
+			got := ""
+			if u != nil {
+				got = u.String()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nProxy(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientCache(t *testing.T) {
+	ctx := context.Background()
+	a := &v1alpha1.Transport{ProxyURL: "http://a.example.org:3128"}
+	b := &v1alpha1.Transport{ProxyURL: "http://b.example.org:3128"}
+
+	first, err := NewHTTPClient(ctx, nil, "cache", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, err := NewHTTPClient(ctx, nil, "cache", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Transport != same.Transport {
+		t.Errorf("NewHTTPClient(...): want the cached transport to be reused while settings are unchanged")
+	}
+
+	changed, err := NewHTTPClient(ctx, nil, "cache", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Transport == changed.Transport {
+		t.Errorf("NewHTTPClient(...): want a new transport when settings change")
+	}
+	if diff := cmp.Diff(1, countTransports("cache")); diff != "" {
+		t.Errorf("NewHTTPClient(...): -want cached transports, +got cached transports:\n%s\n", diff)
+	}
+
+	if _, err := NewHTTPClient(ctx, nil, "cache", nil); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(0, countTransports("cache")); diff != "" {
+		t.Errorf("NewHTTPClient(...): -want cached transports, +got cached transports:\n%s\n", diff)
+	}
+}
+
+func countTransports(name string) int {
+	transports.Lock()
+	defer transports.Unlock()
+	if _, ok := transports.cache[name]; ok {
+		return 1
+	}
+	return 0
+}
@@ -17,23 +17,38 @@ limitations under the License.
 package controller
 
 import (
-	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/salaboy/provider-camunda-cloud/internal/controller/config"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/options"
 	"github.com/salaboy/provider-camunda-cloud/internal/controller/zeebecluster"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/zeebeclusterclaim"
 )
 
-// Setup creates all Template controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger, wl workqueue.RateLimiter) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger, workqueue.RateLimiter) error{
+// Setup creates all Template controllers with the supplied logger and options
+// and adds them to the supplied manager.
+func Setup(mgr ctrl.Manager, l logging.Logger, o options.Options) error {
+	for _, setup := range []func(ctrl.Manager, logging.Logger, options.Options) error{
 		config.Setup,
 		zeebecluster.Setup,
+		zeebeclusterclaim.Setup,
 	} {
-		if err := setup(mgr, l, wl); err != nil {
+		if err := setup(mgr, l, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWebhooks adds all admission webhooks with the supplied logger to the
+// supplied manager's webhook server.
+func SetupWebhooks(mgr ctrl.Manager, l logging.Logger) error {
+	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
+		zeebecluster.SetupWebhook,
+	} {
+		if err := setup(mgr, l); err != nil {
 			return err
 		}
 	}
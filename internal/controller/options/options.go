@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options configures how often and how concurrently the provider's
+// controllers reconcile.
+package options
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+)
+
+const (
+	// DefaultPollInterval is how often a managed resource is observed by
+	// default while it is up to date.
+	DefaultPollInterval = 1 * time.Minute
+
+	// DefaultMaxConcurrentReconciles is how many resources of a kind are
+	// reconciled at once by default.
+	DefaultMaxConcurrentReconciles = 1
+)
+
+// Options configure the provider's controllers.
+type Options struct {
+	// GlobalRateLimiter limits how often the controllers reconcile, all
+	// together.
+	GlobalRateLimiter workqueue.RateLimiter
+
+	// PollInterval is how often a managed resource is observed while it is
+	// up to date, to detect drift.
+	PollInterval time.Duration
+
+	// MaxConcurrentReconciles is how many resources of a kind each
+	// controller reconciles at once.
+	MaxConcurrentReconciles int
+}
+
+// ForControllerRuntime returns the controller-runtime options of a controller.
+// Each controller backs off failing resources on its own, and is limited by
+// the global rate limiter.
+func (o Options) ForControllerRuntime() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(o.GlobalRateLimiter),
+	}
+}
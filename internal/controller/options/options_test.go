@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// A fixedRateLimiter always asks to wait for the same time.
+type fixedRateLimiter time.Duration
+
+func (r fixedRateLimiter) When(interface{}) time.Duration { return time.Duration(r) }
+func (r fixedRateLimiter) Forget(interface{})             {}
+func (r fixedRateLimiter) NumRequeues(interface{}) int    { return 0 }
+
+func TestForControllerRuntime(t *testing.T) {
+	type want struct {
+		maxConcurrentReconciles int
+		first                   time.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      Options
+		want   want
+	}{
+		"GlobalRateLimited": {
+			reason: "Requeues should wait for as long as the global rate limiter asks, if that is longer than the controller's backoff.",
+			o:      Options{GlobalRateLimiter: fixedRateLimiter(time.Minute), MaxConcurrentReconciles: 5},
+			want:   want{maxConcurrentReconciles: 5, first: time.Minute},
+		},
+		"BackedOff": {
+			reason: "Requeues should be backed off by the controller if the global rate limiter does not ask to wait longer.",
+			o:      Options{GlobalRateLimiter: fixedRateLimiter(0), MaxConcurrentReconciles: 1},
+			want:   want{maxConcurrentReconciles: 1, first: time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.o.ForControllerRuntime()
+			if diff := cmp.Diff(tc.want.maxConcurrentReconciles, got.MaxConcurrentReconciles); diff != "" {
+				t.Errorf("\n%s\nForControllerRuntime(): -want MaxConcurrentReconciles, +got MaxConcurrentReconciles:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.first, got.RateLimiter.When("cool")); diff != "" {
+				t.Errorf("\n%s\nForControllerRuntime(): -want first requeue delay, +got first requeue delay:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+// Reasons of the events emitted when an observed cluster changes.
+const (
+	reasonHealthChanged     event.Reason = "HealthChanged"
+	reasonGenerationChanged event.Reason = "GenerationChanged"
+	reasonPlanChanged       event.Reason = "PlanChanged"
+)
+
+// recordChanges emits an event for every change of the cluster's health,
+// generation or plan between the supplied observations. Nothing is emitted for
+// values that were not observed before.
+func recordChanges(r event.Recorder, cr *v1alpha1.ZeebeCluster, before, after v1alpha1.ZeebeClusterObservation) {
+	if prev, cur := before.ClusterStatus.Ready, after.ClusterStatus.Ready; prev != "" && prev != cur {
+		msg := fmt.Sprintf("Cluster health changed from %q to %q", prev, cur)
+		kv := []string{"previous", prev, "current", cur}
+		if cur == "Healthy" || cur == "Creating" {
+			r.Event(cr, event.Normal(reasonHealthChanged, msg, kv...))
+		} else {
+			r.Event(cr, event.Warning(reasonHealthChanged, errors.New(msg), kv...))
+		}
+	}
+	if prev, cur := before.GenerationName, after.GenerationName; prev != "" && prev != cur {
+		r.Event(cr, event.Normal(reasonGenerationChanged,
+			fmt.Sprintf("Cluster generation changed from %q to %q", prev, cur), "previous", prev, "current", cur))
+	}
+	if prev, cur := before.PlanName, after.PlanName; prev != "" && prev != cur {
+		r.Event(cr, event.Normal(reasonPlanChanged,
+			fmt.Sprintf("Cluster plan changed from %q to %q", prev, cur), "previous", prev, "current", cur))
+	}
+}
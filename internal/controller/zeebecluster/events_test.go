@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+type recordedEvent struct {
+	Type    event.Type
+	Reason  event.Reason
+	Message string
+}
+
+type fakeRecorder struct {
+	events []recordedEvent
+}
+
+func (r *fakeRecorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, recordedEvent{Type: e.Type, Reason: e.Reason, Message: e.Message})
+}
+
+func (r *fakeRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestRecordChanges(t *testing.T) {
+	observation := func(ready, generation, plan string) v1alpha1.ZeebeClusterObservation {
+		o := v1alpha1.ZeebeClusterObservation{GenerationName: generation, PlanName: plan}
+		o.ClusterStatus.Ready = ready
+		return o
+	}
+
+	cases := map[string]struct {
+		reason string
+		before v1alpha1.ZeebeClusterObservation
+		after  v1alpha1.ZeebeClusterObservation
+		want   []recordedEvent
+	}{
+		"FirstObservation": {
+			reason: "No events should be emitted for values that were not observed before.",
+			after:  observation("Creating", "Zeebe 1.0.0", "Development"),
+		},
+		"Unchanged": {
+			reason: "No events should be emitted if nothing changed.",
+			before: observation("Healthy", "Zeebe 1.0.0", "Development"),
+			after:  observation("Healthy", "Zeebe 1.0.0", "Development"),
+		},
+		"BecameUnhealthy": {
+			reason: "A warning should be emitted when a cluster stops being healthy.",
+			before: observation("Healthy", "Zeebe 1.0.0", "Development"),
+			after:  observation("Not Healthy", "Zeebe 1.0.0", "Development"),
+			want: []recordedEvent{
+				{Type: event.TypeWarning, Reason: reasonHealthChanged, Message: `Cluster health changed from "Healthy" to "Not Healthy"`},
+			},
+		},
+		"Recovered": {
+			reason: "A normal event should be emitted when a cluster becomes healthy again.",
+			before: observation("Not Healthy", "Zeebe 1.0.0", "Development"),
+			after:  observation("Healthy", "Zeebe 1.0.0", "Development"),
+			want: []recordedEvent{
+				{Type: event.TypeNormal, Reason: reasonHealthChanged, Message: `Cluster health changed from "Not Healthy" to "Healthy"`},
+			},
+		},
+		"Upgraded": {
+			reason: "Generation and plan changes should be emitted with their previous and new values.",
+			before: observation("Healthy", "Zeebe 1.0.0", "Development"),
+			after:  observation("Healthy", "Zeebe 1.1.0", "Production S"),
+			want: []recordedEvent{
+				{Type: event.TypeNormal, Reason: reasonGenerationChanged, Message: `Cluster generation changed from "Zeebe 1.0.0" to "Zeebe 1.1.0"`},
+				{Type: event.TypeNormal, Reason: reasonPlanChanged, Message: `Cluster plan changed from "Development" to "Production S"`},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &fakeRecorder{}
+			recordChanges(r, &v1alpha1.ZeebeCluster{}, tc.before, tc.after)
+			if diff := cmp.Diff(tc.want, r.events); diff != "" {
+				t.Errorf("\n%s\nrecordChanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
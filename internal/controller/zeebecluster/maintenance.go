@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the time zone database, so that maintenance windows work in
+	// images that do not ship one.
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+const (
+	errLoadTimeZone = "cannot load maintenance window time zone"
+	errParseStart   = "cannot parse maintenance window start, want HH:MM"
+	errMinDuration  = "maintenance window duration must be at least a minute"
+)
+
+// reasonChangesDue is the reason of the event emitted when changes of a cluster
+// are due, but must be made in the Camunda Console.
+const reasonChangesDue event.Reason = "ChangesDue"
+
+// Fields of a cluster that can be changed.
+const (
+	fieldPlan       = "plan"
+	fieldChannel    = "channel"
+	fieldGeneration = "generation"
+)
+
+// A change of a cluster that is needed to make it match its ZeebeCluster.
+type change struct {
+	field string
+	from  string
+	to    string
+
+	// disruptive changes restart the cluster's brokers, and are only made
+	// in its maintenance window.
+	disruptive bool
+}
+
+func (c change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.field, c.from, c.to)
+}
+
+// changes returns the changes needed to make the observed cluster match the
+// supplied parameters. Parameters that are not set are not changed. Those
+// that are set by ID are compared by ID, the others by name. Camunda upgrades
+// the generation of clusters, so a generation name older than the observed one
+// is never asked for.
+func changes(p v1alpha1.ZeebeClusterParameters, o v1alpha1.ZeebeClusterObservation) []change {
+	equal := func(observed, desired string) bool { return observed == desired }
+	var cs []change
+	if p.GenerationId == "" && isDowngrade(o.GenerationName, p.GenerationName) {
+		p.GenerationName = ""
+	}
+	for _, f := range []struct {
+		field              string
+		id, observedID     string
+		name, observedName string
+		matches            func(observed, desired string) bool
+		disruptive         bool
+	}{
+		{field: fieldGeneration, id: p.GenerationId, observedID: o.GenerationId, name: p.GenerationName, observedName: o.GenerationName, matches: equal, disruptive: true},
+		{field: fieldPlan, id: p.PlanId, observedID: o.PlanId, name: p.PlanName, observedName: o.PlanName, matches: equal, disruptive: true},
+		// Channels are matched the way they are when creating a cluster, by a
+		// part of their name.
+		{field: fieldChannel, id: p.ChannelId, observedID: o.ChannelId, name: p.ChannelName, observedName: o.ChannelName, matches: strings.Contains},
+	} {
+		desired, observed, matches := f.name, f.observedName, f.matches
+		if f.id != "" {
+			desired, observed, matches = f.id, f.observedID, equal
+		}
+		if desired != "" && observed != "" && !matches(observed, desired) {
+			cs = append(cs, change{field: f.field, from: observed, to: desired, disruptive: f.disruptive})
+		}
+	}
+	return cs
+}
+
+// schedule splits the supplied changes into those that may be made now and
+// those that must wait for the next maintenance window, which is returned if
+// any changes must wait. Disruptive changes may be made at any time if no
+// window is set.
+func schedule(cs []change, w *v1alpha1.MaintenanceWindow, now time.Time) (apply, deferred []change, next *time.Time, err error) {
+	open := true
+	for _, c := range cs {
+		if c.disruptive && w != nil {
+			open, next, err = inWindow(w, now)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			break
+		}
+	}
+	for _, c := range cs {
+		if c.disruptive && !open {
+			deferred = append(deferred, c)
+			continue
+		}
+		apply = append(apply, c)
+	}
+	if len(deferred) == 0 {
+		next = nil
+	}
+	return apply, deferred, next, nil
+}
+
+// inWindow returns whether the supplied maintenance window is open at the
+// supplied time, and when it opens next.
+func inWindow(w *v1alpha1.MaintenanceWindow, now time.Time) (bool, *time.Time, error) {
+	loc := time.UTC
+	if w.TimeZone != "" {
+		l, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return false, nil, errors.Wrap(err, errLoadTimeZone)
+		}
+		loc = l
+	}
+	if w.Duration.Duration < time.Minute {
+		return false, nil, errors.New(errMinDuration)
+	}
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false, nil, errors.Wrap(err, errParseStart)
+	}
+
+	days := map[time.Weekday]bool{}
+	for _, d := range w.Days {
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if strings.EqualFold(wd.String(), string(d)) {
+				days[wd] = true
+			}
+		}
+	}
+
+	local := now.In(loc)
+	open := false
+	var next *time.Time
+
+	// A window that opened yesterday may still be open. Windows open at
+	// least once a week.
+	for offset := -1; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		if len(days) > 0 && !days[day.Weekday()] {
+			continue
+		}
+		opens := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		if !now.Before(opens) && now.Before(opens.Add(w.Duration.Duration)) {
+			open = true
+		}
+		if opens.After(now) && next == nil {
+			t := opens
+			next = &t
+		}
+	}
+	return open, next, nil
+}
+
+// describe returns a human readable description of each supplied change.
+func describe(cs []change) []string {
+	if len(cs) == 0 {
+		return nil
+	}
+	d := make([]string, len(cs))
+	for i, c := range cs {
+		d[i] = c.String()
+	}
+	return d
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+func TestChanges(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.ZeebeClusterParameters
+		o      v1alpha1.ZeebeClusterObservation
+		want   []change
+	}{
+		"UpToDate": {
+			reason: "No changes should be needed if the cluster matches its parameters, or a parameter is not set.",
+			p:      v1alpha1.ZeebeClusterParameters{PlanName: "Development", ChannelName: "Stable"},
+			o:      v1alpha1.ZeebeClusterObservation{PlanName: "Development", ChannelName: "Stable", GenerationName: "Zeebe 1.0.0"},
+		},
+		"PartialChannelName": {
+			reason: "A channel should be matched by a part of its name, as it is when creating a cluster.",
+			p:      v1alpha1.ZeebeClusterParameters{ChannelName: "Alpha"},
+			o:      v1alpha1.ZeebeClusterObservation{ChannelName: "Alpha (Preview)"},
+		},
+		"AllChanged": {
+			reason: "Generation and plan changes should be disruptive, channel changes should not.",
+			p:      v1alpha1.ZeebeClusterParameters{PlanName: "Production S", ChannelName: "Alpha", GenerationName: "Zeebe 1.1.0"},
+			o:      v1alpha1.ZeebeClusterObservation{PlanName: "Development", ChannelName: "Stable", GenerationName: "Zeebe 1.0.0"},
+			want: []change{
+				{field: fieldGeneration, from: "Zeebe 1.0.0", to: "Zeebe 1.1.0", disruptive: true},
+				{field: fieldPlan, from: "Development", to: "Production S", disruptive: true},
+				{field: fieldChannel, from: "Stable", to: "Alpha"},
+			},
+		},
+		"UpgradedByCamunda": {
+			reason: "A cluster that Camunda upgraded past the desired generation should not be downgraded.",
+			p:      v1alpha1.ZeebeClusterParameters{GenerationName: "Zeebe 1.0.0"},
+			o:      v1alpha1.ZeebeClusterObservation{GenerationName: "Zeebe 1.1.0"},
+		},
+		"ByID": {
+			reason: "Parameters set by ID should be compared by ID, even if a stale name is set too.",
+			p: v1alpha1.ZeebeClusterParameters{
+				PlanName: "Development", PlanId: "plan-production-s",
+				ChannelId:    "channel-stable",
+				GenerationId: "generation-zeebe-1-1-0",
+			},
+			o: v1alpha1.ZeebeClusterObservation{
+				PlanName: "Development", PlanId: "plan-development",
+				ChannelName: "Stable", ChannelId: "channel-stable",
+				GenerationName: "Zeebe 1.0.0", GenerationId: "generation-zeebe-1-0-0",
+			},
+			want: []change{
+				{field: fieldGeneration, from: "generation-zeebe-1-0-0", to: "generation-zeebe-1-1-0", disruptive: true},
+				{field: fieldPlan, from: "plan-development", to: "plan-production-s", disruptive: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := changes(tc.p, tc.o)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(change{})); diff != "" {
+				t.Errorf("\n%s\nchanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2021, time.March, 3, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) *time.Time { return &t }
+
+	upgrade := change{field: fieldGeneration, from: "Zeebe 1.0.0", to: "Zeebe 1.1.0", disruptive: true}
+	channel := change{field: fieldChannel, from: "Stable", to: "Alpha"}
+
+	type want struct {
+		apply    []change
+		deferred []change
+		next     *time.Time
+		err      bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		cs     []change
+		w      *v1alpha1.MaintenanceWindow
+		want   want
+	}{
+		"NoWindow": {
+			reason: "All changes should be made right away if no maintenance window is set.",
+			cs:     []change{upgrade, channel},
+			want:   want{apply: []change{upgrade, channel}},
+		},
+		"WindowOpen": {
+			reason: "Disruptive changes should be made while the maintenance window is open.",
+			cs:     []change{upgrade},
+			w:      &v1alpha1.MaintenanceWindow{Start: "11:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			want:   want{apply: []change{upgrade}},
+		},
+		"WindowOpenSinceYesterday": {
+			reason: "A maintenance window that opened the day before should still be open.",
+			cs:     []change{upgrade},
+			w: &v1alpha1.MaintenanceWindow{
+				Days:     []v1alpha1.Weekday{"Tuesday"},
+				Start:    "22:00",
+				Duration: metav1.Duration{Duration: 15 * time.Hour},
+			},
+			want: want{apply: []change{upgrade}},
+		},
+		"WindowClosed": {
+			reason: "Disruptive changes should wait for the next window while non-disruptive changes are made right away.",
+			cs:     []change{upgrade, channel},
+			w: &v1alpha1.MaintenanceWindow{
+				Days:     []v1alpha1.Weekday{"Saturday", "Sunday"},
+				Start:    "02:00",
+				Duration: metav1.Duration{Duration: 4 * time.Hour},
+			},
+			want: want{
+				apply:    []change{channel},
+				deferred: []change{upgrade},
+				next:     at(time.Date(2021, time.March, 6, 2, 0, 0, 0, time.UTC)),
+			},
+		},
+		"TimeZone": {
+			reason: "The window should open at its start time in its time zone.",
+			cs:     []change{upgrade},
+			w: &v1alpha1.MaintenanceWindow{
+				Start:    "20:00",
+				Duration: metav1.Duration{Duration: time.Hour},
+				TimeZone: "Europe/Berlin",
+			},
+			want: want{
+				deferred: []change{upgrade},
+				next:     at(time.Date(2021, time.March, 3, 19, 0, 0, 0, time.UTC)),
+			},
+		},
+		"NoDuration": {
+			reason: "An error should be returned rather than never opening a window that lasts less than a minute.",
+			cs:     []change{upgrade},
+			w:      &v1alpha1.MaintenanceWindow{Start: "20:00"},
+			want:   want{err: true},
+		},
+		"InvalidTimeZone": {
+			reason: "An error should be returned if the window's time zone does not exist.",
+			cs:     []change{upgrade},
+			w:      &v1alpha1.MaintenanceWindow{Start: "20:00", TimeZone: "Mars/Olympus_Mons"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			apply, deferred, next, err := schedule(tc.cs, tc.w, now)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nschedule(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.apply, apply, cmp.AllowUnexported(change{})); diff != "" {
+				t.Errorf("\n%s\nschedule(...): -want apply, +got apply:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deferred, deferred, cmp.AllowUnexported(change{})); diff != "" {
+				t.Errorf("\n%s\nschedule(...): -want deferred, +got deferred:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.next == nil || next == nil {
+				if tc.want.next != next {
+					t.Errorf("\n%s\nschedule(...): want next window %v, got %v", tc.reason, tc.want.next, next)
+				}
+				return
+			}
+			if !tc.want.next.Equal(*next) {
+				t.Errorf("\n%s\nschedule(...): want next window %v, got %v", tc.reason, tc.want.next, next)
+			}
+		})
+	}
+}
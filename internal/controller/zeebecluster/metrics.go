@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+// readyUnknown is reported for clusters whose status was not observed yet.
+const readyUnknown = "Unknown"
+
+var timeToHealthy = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: camunda.MetricsNamespace,
+	Subsystem: "zeebecluster",
+	Name:      "time_to_healthy_seconds",
+	Help:      "Time from creating a Zeebe cluster to first observing it Healthy.",
+	Buckets:   prometheus.ExponentialBuckets(30, 2, 8),
+})
+
+func init() {
+	metrics.Registry.MustRegister(timeToHealthy)
+}
+
+// observeHealthy records how long the supplied cluster took to become healthy
+// if it was still being created. It must be called before the cluster's Ready
+// condition is updated.
+func observeHealthy(cr *v1alpha1.ZeebeCluster) {
+	c := cr.GetCondition(xpv1.TypeReady)
+	if c.Reason != xpv1.ReasonCreating || c.LastTransitionTime.IsZero() {
+		return
+	}
+	timeToHealthy.Observe(time.Since(c.LastTransitionTime.Time).Seconds())
+}
+
+// A clusterCollector reports the number of ZeebeClusters by the Ready value of
+// their observed status. It reads ZeebeClusters from the manager's cache when
+// metrics are scraped.
+type clusterCollector struct {
+	mu   sync.RWMutex
+	kube client.Reader
+	log  logging.Logger
+	desc *prometheus.Desc
+}
+
+func newClusterCollector(kube client.Reader, log logging.Logger) *clusterCollector {
+	return &clusterCollector{
+		kube: kube,
+		log:  log,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(camunda.MetricsNamespace, "zeebecluster", "clusters"),
+			"Number of ZeebeClusters by the Ready value of their observed status.",
+			[]string{"ready"}, nil,
+		),
+	}
+}
+
+// registerClusterCollector registers a clusterCollector that reads
+// ZeebeClusters through the supplied client. If one is registered already,
+// because the controller was set up again, it reads through the supplied
+// client from now on.
+func registerClusterCollector(reg prometheus.Registerer, kube client.Reader, log logging.Logger) error {
+	err := reg.Register(newClusterCollector(kube, log))
+	var are prometheus.AlreadyRegisteredError
+	if !errors.As(err, &are) {
+		return err
+	}
+	c, ok := are.ExistingCollector.(*clusterCollector)
+	if !ok {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kube, c.log = kube, log
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *clusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *clusterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	c.mu.RLock()
+	kube, log := c.kube, c.log
+	c.mu.RUnlock()
+
+	l := &v1alpha1.ZeebeClusterList{}
+	if err := kube.List(ctx, l); err != nil {
+		log.Debug("Cannot list ZeebeClusters", "error", err)
+		return
+	}
+
+	counts := map[string]int{}
+	for _, cr := range l.Items {
+		ready := cr.Status.AtProvider.ClusterStatus.Ready
+		if ready == "" {
+			ready = readyUnknown
+		}
+		counts[ready]++
+	}
+
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	for _, v := range values {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[v]), v)
+	}
+}
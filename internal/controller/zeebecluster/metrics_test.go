@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+func TestClusterCollector(t *testing.T) {
+	cluster := func(ready string) v1alpha1.ZeebeCluster {
+		cr := v1alpha1.ZeebeCluster{}
+		cr.Status.AtProvider.ClusterStatus.Ready = ready
+		return cr
+	}
+
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*v1alpha1.ZeebeClusterList).Items = []v1alpha1.ZeebeCluster{
+				cluster("Healthy"),
+				cluster("Healthy"),
+				cluster("Not Healthy"),
+				cluster(""),
+			}
+			return nil
+		},
+	}
+
+	want := `
+# HELP provider_camunda_cloud_zeebecluster_clusters Number of ZeebeClusters by the Ready value of their observed status.
+# TYPE provider_camunda_cloud_zeebecluster_clusters gauge
+provider_camunda_cloud_zeebecluster_clusters{ready="Healthy"} 2
+provider_camunda_cloud_zeebecluster_clusters{ready="Not Healthy"} 1
+provider_camunda_cloud_zeebecluster_clusters{ready="Unknown"} 1
+`
+	if err := testutil.CollectAndCompare(newClusterCollector(kube, logging.NewNopLogger()), strings.NewReader(want)); err != nil {
+		t.Errorf("clusterCollector.Collect(...): %v", err)
+	}
+}
+
+func TestRegisterClusterCollector(t *testing.T) {
+	// clusters returns a client that lists the supplied number of
+	// ZeebeClusters.
+	clusters := func(n int) client.Reader {
+		return &test.MockClient{
+			MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+				obj.(*v1alpha1.ZeebeClusterList).Items = make([]v1alpha1.ZeebeCluster, n)
+				return nil
+			},
+		}
+	}
+
+	reg := prometheus.NewRegistry()
+	if err := registerClusterCollector(reg, clusters(1), logging.NewNopLogger()); err != nil {
+		t.Fatalf("registerClusterCollector(...): %v", err)
+	}
+
+	// The controller is set up again, for example by another manager.
+	if err := registerClusterCollector(reg, clusters(2), logging.NewNopLogger()); err != nil {
+		t.Fatalf("registerClusterCollector(...): registering again: %v", err)
+	}
+
+	want := `
+# HELP provider_camunda_cloud_zeebecluster_clusters Number of ZeebeClusters by the Ready value of their observed status.
+# TYPE provider_camunda_cloud_zeebecluster_clusters gauge
+provider_camunda_cloud_zeebecluster_clusters{ready="Unknown"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Errorf("registerClusterCollector(...): want ZeebeClusters read through the latest client: %v", err)
+	}
+}
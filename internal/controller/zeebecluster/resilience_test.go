@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+	"github.com/salaboy/provider-camunda-cloud/internal/fake/console"
+)
+
+// maxReconciles is how many reconciles a ZeebeCluster may take to become
+// available, or to be deleted, despite the injected faults.
+const maxReconciles = 20
+
+// newConnector returns a connector whose ProviderConfig logs in to the
+// supplied fake Console API.
+func newConnector(srv *httptest.Server) *connector {
+	creds, _ := json.Marshal(CCCredentials{CCClientId: console.DefaultClientID, CCSecretId: console.DefaultClientSecret})
+	pc := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{Key: "credentials"},
+				},
+			},
+			Endpoints: &apisv1alpha1.Endpoints{Login: srv.URL + console.LoginPath, API: srv.URL},
+			// Do not let rate limiting slow the test down.
+			RateLimit: &apisv1alpha1.RateLimit{RequestsPerMinute: 60000, Burst: 1000},
+		},
+	}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *apisv1alpha1.ProviderConfig:
+				pc.DeepCopyInto(o)
+			case *corev1.Secret:
+				o.Data = map[string][]byte{"credentials": creds}
+			}
+			return nil
+		},
+	}
+	return &connector{
+		kube:      kube,
+		usage:     resource.TrackerFn(func(context.Context, resource.Managed) error { return nil }),
+		throttles: camunda.NewThrottles(),
+		tokens:    camunda.NewTokens(),
+		log:       logging.NewNopLogger(),
+		recorder:  event.NewNopRecorder(),
+	}
+}
+
+// reconcileOnce connects to the Console API and observes the supplied
+// ZeebeCluster, then creates, updates or deletes its cluster, the way the
+// managed reconciler does. It returns true once the ZeebeCluster is deleted
+// and its finalizer would be removed.
+func reconcileOnce(ctx context.Context, c managed.ExternalConnecter, cr *v1alpha1.ZeebeCluster) (bool, error) {
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	o, err := ext.Observe(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if meta.WasDeleted(cr) {
+		if o.ResourceExists {
+			return false, ext.Delete(ctx, cr)
+		}
+		return true, nil
+	}
+	if !o.ResourceExists {
+		_, err := ext.Create(ctx, cr)
+		return false, err
+	}
+	if !o.ResourceUpToDate {
+		_, err := ext.Update(ctx, cr)
+		return false, err
+	}
+	return false, nil
+}
+
+// backOff waits for as long as the Console API asked to, like the managed
+// reconciler does before it requeues a throttled resource.
+func backOff(err error) {
+	if d, ok := camunda.RetryAfter(err); ok {
+		time.Sleep(d)
+	}
+}
+
+// pending returns how many of the supplied faults that affect any request
+// are not used up yet.
+func pending(f console.Faults) int {
+	return f.ServerErrors + f.Throttled + f.ExpireTokensAfter
+}
+
+func TestResilience(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		faults console.Faults
+	}{
+		"NoFaults": {
+			reason: "A cluster should be created once and deleted when the Console API works.",
+		},
+		"Latency": {
+			reason: "A cluster should be created once and deleted when the Console API is slow.",
+			faults: console.Faults{Latency: 20 * time.Millisecond},
+		},
+		"ServerErrors": {
+			reason: "A cluster should be created once and deleted despite a burst of server errors.",
+			faults: console.Faults{ServerErrors: 3},
+		},
+		"BadGateway": {
+			reason: "A cluster should be created once and deleted despite a burst of gateway errors.",
+			faults: console.Faults{ServerErrors: 2, ServerErrorStatus: http.StatusBadGateway},
+		},
+		"Throttled": {
+			reason: "A cluster should be created once and deleted despite the Console API asking to back off.",
+			faults: console.Faults{Throttled: 2, RetryAfter: time.Second},
+		},
+		"TokenExpiresMidReconcile": {
+			reason: "A cluster should be created once and deleted despite the access token expiring between requests of a reconcile.",
+			faults: console.Faults{ExpireTokensAfter: 1},
+		},
+		"PartialCreate": {
+			reason: "A cluster whose create request failed after the Console accepted it should not be created again.",
+			faults: console.Faults{PartialCreates: 1},
+		},
+		"Outage": {
+			reason: "A cluster should be created once and deleted despite all of the above.",
+			faults: console.Faults{
+				Latency:           10 * time.Millisecond,
+				ServerErrors:      2,
+				Throttled:         1,
+				RetryAfter:        time.Second,
+				ExpireTokensAfter: 3,
+				PartialCreates:    1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			con := console.New(console.WithCreatingFor(0), console.WithDeletingFor(0), console.WithFaults(tc.faults))
+			srv := httptest.NewServer(con)
+			defer srv.Close()
+
+			ctx := context.Background()
+			c := newConnector(srv)
+			cr := zeebeCluster(withParameters(params))
+			cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+			for i := 0; !cr.GetCondition(xpv1.TypeReady).Equal(xpv1.Available()); i++ {
+				if i == maxReconciles {
+					t.Fatalf("\n%s\nthe ZeebeCluster did not become available in %d reconciles: %+v", tc.reason, maxReconciles, cr.Status)
+				}
+				_, err := reconcileOnce(ctx, c, cr)
+				if cl := con.Clusters(); len(cl) > 1 {
+					t.Fatalf("\n%s\nreconcileOnce(...): want at most one cluster, got %d: %+v", tc.reason, len(cl), cl)
+				}
+				backOff(err)
+			}
+
+			if f := con.Faults(); pending(f) != 0 || f.PartialCreates != 0 {
+				t.Errorf("\n%s\nnot all faults were injected while creating the cluster: %+v", tc.reason, f)
+			}
+			cl := con.Clusters()
+			if len(cl) != 1 {
+				t.Fatalf("\n%s\nwant exactly one cluster once the ZeebeCluster is available, got %d", tc.reason, len(cl))
+			}
+			if cr.Status.AtProvider.ClusterId != cl[0].ID {
+				t.Errorf("\n%s\nstatus.atProvider.clusterId: want %q, got %q", tc.reason, cl[0].ID, cr.Status.AtProvider.ClusterId)
+			}
+
+			con.Inject(tc.faults)
+			now := metav1.Now()
+			cr.SetDeletionTimestamp(&now)
+			for i := 0; ; i++ {
+				if i == maxReconciles {
+					t.Fatalf("\n%s\nthe ZeebeCluster was not deleted in %d reconciles: %+v", tc.reason, maxReconciles, cr.Status)
+				}
+				gone, err := reconcileOnce(ctx, c, cr)
+				if gone {
+					break
+				}
+				backOff(err)
+			}
+			if f := con.Faults(); pending(f) != 0 {
+				t.Errorf("\n%s\nnot all faults were injected while deleting the cluster: %+v", tc.reason, f)
+			}
+			if cl := con.Clusters(); len(cl) != 0 {
+				t.Errorf("\n%s\nwant no orphaned clusters once the ZeebeCluster is deleted, got %+v", tc.reason, cl)
+			}
+		})
+	}
+}
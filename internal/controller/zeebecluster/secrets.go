@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+const listTimeout = 30 * time.Second
+
+// secretIndex indexes ProviderConfigs by the Secrets they reference, so that
+// a changed Secret is mapped to the ProviderConfigs that use it without
+// listing every ProviderConfig.
+const secretIndex = "spec.secretRefs"
+
+// indexSecretRefs returns the keys of the Secrets referenced by the supplied
+// ProviderConfig, either for its credentials or its CA bundle.
+func indexSecretRefs(o client.Object) []string {
+	pc, ok := o.(*apisv1alpha1.ProviderConfig)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	if ref := pc.Spec.Credentials.SecretRef; ref != nil {
+		keys = append(keys, secretKey(ref.Namespace, ref.Name))
+	}
+	if t := pc.Spec.Transport; t != nil && t.CABundleSecretRef != nil {
+		keys = append(keys, secretKey(t.CABundleSecretRef.Namespace, t.CABundleSecretRef.Name))
+	}
+	return keys
+}
+
+func secretKey(namespace, name string) string {
+	return types.NamespacedName{Namespace: namespace, Name: name}.String()
+}
+
+// requestsForSecret returns a function that maps a changed Secret to requests
+// for every managed resource of the supplied kind whose ProviderConfig
+// references that Secret, either for its credentials or its CA bundle. Cached
+// access tokens of those ProviderConfigs are dropped, so that rotated
+// credentials are used by the very next reconcile. ProviderConfigs are looked
+// up by the secretIndex, so Secrets no ProviderConfig references are ignored.
+func requestsForSecret(kube client.Client, log logging.Logger, tokens *camunda.Tokens, kind string) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+		defer cancel()
+
+		pcs := &apisv1alpha1.ProviderConfigList{}
+		if err := kube.List(ctx, pcs, client.MatchingFields{secretIndex: secretKey(o.GetNamespace(), o.GetName())}); err != nil {
+			log.Debug("Cannot list ProviderConfigs", "error", err)
+			return nil
+		}
+
+		var reqs []reconcile.Request
+		for _, pc := range pcs.Items {
+			tokens.Invalidate(pc.GetName())
+
+			pcus := &apisv1alpha1.ProviderConfigUsageList{}
+			if err := kube.List(ctx, pcus, client.MatchingLabels{xpv1.LabelKeyProviderName: pc.GetName()}); err != nil {
+				log.Debug("Cannot list ProviderConfigUsages", "error", err, "provider-config", pc.GetName())
+				continue
+			}
+			for _, pcu := range pcus.Items {
+				if pcu.ResourceReference.Kind != kind {
+					continue
+				}
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: pcu.ResourceReference.Name}})
+			}
+		}
+		return reqs
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+func TestRequestsForSecret(t *testing.T) {
+	secretRef := func(name string) apisv1alpha1.ProviderConfig {
+		pc := apisv1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		pc.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: name},
+			Key:             "credentials",
+		}
+		return pc
+	}
+	usage := func(pc, kind, name string) apisv1alpha1.ProviderConfigUsage {
+		u := apisv1alpha1.ProviderConfigUsage{}
+		u.ProviderConfigReference = xpv1.Reference{Name: pc}
+		u.ResourceReference = xpv1.TypedReference{Kind: kind, Name: name}
+		return u
+	}
+
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *apisv1alpha1.ProviderConfigList:
+				// Only ProviderConfigs the secretIndex matches are listed.
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				if lo.FieldSelector == nil {
+					return errors.New("ProviderConfigs listed without the secretIndex")
+				}
+				for _, pc := range []apisv1alpha1.ProviderConfig{secretRef("rotated"), secretRef("other")} {
+					for _, k := range indexSecretRefs(pc.DeepCopy()) {
+						if lo.FieldSelector.Matches(fields.Set{secretIndex: k}) {
+							l.Items = append(l.Items, pc)
+						}
+					}
+				}
+			case *apisv1alpha1.ProviderConfigUsageList:
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				if lo.LabelSelector.String() != xpv1.LabelKeyProviderName+"=rotated" {
+					return nil
+				}
+				l.Items = []apisv1alpha1.ProviderConfigUsage{
+					usage("rotated", v1alpha1.ZeebeClusterKind, "a"),
+					usage("rotated", "SomethingElse", "b"),
+					usage("rotated", v1alpha1.ZeebeClusterKind, "c"),
+				}
+			}
+			return nil
+		},
+	}
+
+	tokens := camunda.NewTokens()
+	fp := camunda.FingerprintOf([]byte("creds"))
+	tokens.Set("rotated", fp, "stale", time.Now().Add(time.Hour))
+	tokens.Set("other", fp, "fresh", time.Now().Add(time.Hour))
+
+	fn := requestsForSecret(kube, logging.NewNopLogger(), tokens, v1alpha1.ZeebeClusterKind)
+	got := fn(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "rotated"}})
+
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "a"}},
+		{NamespacedName: types.NamespacedName{Name: "c"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("requestsForSecret(...): -want, +got:\n%s\n", diff)
+	}
+	if _, ok := tokens.Get("rotated", fp); ok {
+		t.Errorf("requestsForSecret(...): token of ProviderConfig referencing the rotated Secret was not dropped")
+	}
+	if _, ok := tokens.Get("other", fp); !ok {
+		t.Errorf("requestsForSecret(...): token of unrelated ProviderConfig was dropped")
+	}
+
+	if got := fn(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "unrelated"}}); got != nil {
+		t.Errorf("requestsForSecret(...): Secret no ProviderConfig references mapped to %v", got)
+	}
+}
+
+func TestIndexSecretRefs(t *testing.T) {
+	creds := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "creds"}, Key: "credentials"}
+	ca := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "ca"}, Key: "ca.crt"}
+
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotAProviderConfig": {
+			reason: "Objects other than ProviderConfigs should not be indexed.",
+			o:      &corev1.Secret{},
+		},
+		"NoSecrets": {
+			reason: "A ProviderConfig that references no Secrets should not be indexed.",
+			o:      &apisv1alpha1.ProviderConfig{},
+		},
+		"Credentials": {
+			reason: "A ProviderConfig should be indexed by its credentials Secret.",
+			o: func() client.Object {
+				pc := &apisv1alpha1.ProviderConfig{}
+				pc.Spec.Credentials.SecretRef = creds
+				return pc
+			}(),
+			want: []string{"crossplane-system/creds"},
+		},
+		"CredentialsAndCABundle": {
+			reason: "A ProviderConfig should be indexed by both its credentials and its CA bundle Secret.",
+			o: func() client.Object {
+				pc := &apisv1alpha1.ProviderConfig{}
+				pc.Spec.Credentials.SecretRef = creds
+				pc.Spec.Transport = &apisv1alpha1.Transport{CABundleSecretRef: ca}
+				return pc
+			}(),
+			want: []string{"crossplane-system/creds", "crossplane-system/ca"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := indexSecretRefs(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nindexSecretRefs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// retries records when the in-flight reconcile of each ZeebeCluster that was
+// throttled by the Console API should be retried. The managed reconciler
+// requeues failed reconciles with the controller's backoff, which knows
+// nothing about the Console API's Retry-After, so it is recorded here.
+var retries sync.Map
+
+// A throttledReconciler requeues reconciles that were throttled by the
+// Console API for when the Console API accepts requests again.
+type throttledReconciler struct {
+	wrapped reconcile.Reconciler
+	log     logging.Logger
+}
+
+// Reconcile the supplied request, requeueing it for when the Console API
+// accepts requests again if it was throttled.
+func (r *throttledReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	retries.Delete(req.Name)
+	result, err := r.wrapped.Reconcile(ctx, req)
+
+	v, ok := retries.Load(req.Name)
+	if !ok {
+		return result, err
+	}
+	retries.Delete(req.Name)
+
+	// Errors are returned as is, so that a status that could not be
+	// persisted is retried with the controller's backoff.
+	if err != nil {
+		return result, err
+	}
+	// The Throttled condition does not say when the reconcile is retried,
+	// so that it does not change on every throttled reconcile.
+	at := v.(time.Time)
+	r.log.Debug("Console API requests are throttled", "resource", req.Name, "retry-at", at)
+	if d := time.Until(at); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+func TestThrottledReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// throttle returns a reconciler that is throttled until the supplied
+	// time, and returns the supplied result and error.
+	throttle := func(at time.Time, result reconcile.Result, err error) reconcile.Func {
+		return func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			setThrottled(zeebeCluster(), &camunda.ThrottledError{StatusCode: 429, RetryAt: at})
+			return result, err
+		}
+	}
+
+	type want struct {
+		requeue bool
+		after   time.Duration
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		wrapped reconcile.Reconciler
+		want    want
+	}{
+		"NotThrottled": {
+			reason: "The result of reconciles that were not throttled should be returned as is.",
+			wrapped: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: time.Hour}, nil
+			}),
+			want: want{after: time.Hour},
+		},
+		"Throttled": {
+			reason:  "Throttled reconciles should be requeued for when the Console API accepts requests again.",
+			wrapped: throttle(time.Now().Add(time.Minute), reconcile.Result{Requeue: true}, nil),
+			want:    want{after: time.Minute},
+		},
+		"ThrottlingOver": {
+			reason:  "Throttled reconciles should be requeued immediately if the Console API already accepts requests again.",
+			wrapped: throttle(time.Now().Add(-time.Minute), reconcile.Result{Requeue: true}, nil),
+			want:    want{requeue: true},
+		},
+		"Error": {
+			reason:  "Errors of throttled reconciles should be returned, so that they are retried with the controller's backoff.",
+			wrapped: throttle(time.Now().Add(time.Minute), reconcile.Result{}, errBoom),
+			want:    want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &throttledReconciler{wrapped: tc.wrapped, log: logging.NewNopLogger()}
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requeue, got.Requeue); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want requeue, +got requeue:\n%s\n", tc.reason, diff)
+			}
+			// Allow for the time it took to reconcile.
+			if got.RequeueAfter > tc.want.after || got.RequeueAfter < tc.want.after-time.Second {
+				t.Errorf("\n%s\nr.Reconcile(...): want requeue after %s, got %s", tc.reason, tc.want.after, got.RequeueAfter)
+			}
+			if _, ok := retries.Load(clusterName); ok {
+				t.Errorf("\n%s\nr.Reconcile(...): want retry time to be forgotten after the reconcile", tc.reason)
+			}
+		})
+	}
+}
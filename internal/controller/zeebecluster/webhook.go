@@ -0,0 +1,382 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+const (
+	// ValidatingWebhookPath is the path the ZeebeCluster validating webhook
+	// is served at.
+	ValidatingWebhookPath = "/validate-cc-camunda-crossplane-io-v1alpha1-zeebecluster"
+
+	// ConversionWebhookPath is the path the webhook that converts between
+	// versions of ZeebeCluster is served at.
+	ConversionWebhookPath = "/convert"
+
+	// paramsTTL is how long the cluster parameters offered by the Console
+	// API are cached for.
+	paramsTTL = 10 * time.Minute
+
+	// defaultProviderConfig is the ProviderConfig a ZeebeCluster uses if it
+	// does not reference one.
+	defaultProviderConfig = "default"
+
+	errNewDecoder       = "cannot create admission decoder"
+	errGetClusterParams = "cannot get cluster parameters"
+	errCheckParams      = "cannot check cluster parameters against those offered by Camunda Cloud"
+	errImmutable        = "field is immutable"
+	errDowngrade        = "cannot downgrade from %q to %q"
+)
+
+// SetupWebhook adds a validating admission webhook and a conversion webhook
+// for ZeebeClusters to the supplied manager's webhook server.
+func SetupWebhook(mgr ctrl.Manager, l logging.Logger) error {
+	name := "validate/" + v1alpha1.ZeebeClusterGroupKind
+	log := l.WithValues("webhook", name)
+
+	d, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return errors.Wrap(err, errNewDecoder)
+	}
+	c := &connector{
+		kube:      mgr.GetClient(),
+		throttles: camunda.NewThrottles(),
+		tokens:    camunda.NewTokens(),
+		log:       log,
+		recorder:  event.NewNopRecorder(),
+	}
+	v := &validator{
+		params:  newParamsCache(c.clusterParams, paramsTTL),
+		decoder: d,
+		log:     log,
+	}
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: v})
+	mgr.GetWebhookServer().Register(ConversionWebhookPath, &conversion.Webhook{})
+	return nil
+}
+
+// clusterParams returns the cluster parameters that the Console API offers
+// to the credentials of the named ProviderConfig.
+func (c *connector) clusterParams(ctx context.Context, providerConfig string) (*cc.ClusterParams, error) {
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: providerConfig}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	svc, err := c.login(ctx, pc, c.log.WithValues("provider-config", providerConfig))
+	if err != nil {
+		return nil, err
+	}
+	p, err := svc.GetClusterParamsWithContext(ctx)
+	return p, errors.Wrap(err, errGetClusterParams)
+}
+
+// A paramsGetter returns the cluster parameters that the Console API offers
+// to the credentials of the named ProviderConfig.
+type paramsGetter interface {
+	Get(ctx context.Context, providerConfig string) (*cc.ClusterParams, error)
+}
+
+type cachedParams struct {
+	params  *cc.ClusterParams
+	fetched time.Time
+}
+
+// A paramsCache caches cluster parameters per ProviderConfig, so that the
+// webhook does not call the Console API for every admission request.
+type paramsCache struct {
+	fetch func(ctx context.Context, providerConfig string) (*cc.ClusterParams, error)
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu sync.Mutex
+	m  map[string]cachedParams
+}
+
+func newParamsCache(fetch func(ctx context.Context, providerConfig string) (*cc.ClusterParams, error), ttl time.Duration) *paramsCache {
+	return &paramsCache{fetch: fetch, ttl: ttl, now: time.Now, m: map[string]cachedParams{}}
+}
+
+// Get returns the cached cluster parameters of the named ProviderConfig,
+// fetching them if they are not cached or are older than the cache's TTL.
+// Errors are not cached.
+func (pc *paramsCache) Get(ctx context.Context, providerConfig string) (*cc.ClusterParams, error) {
+	pc.mu.Lock()
+	cp, ok := pc.m[providerConfig]
+	pc.mu.Unlock()
+	if ok && pc.now().Before(cp.fetched.Add(pc.ttl)) {
+		return cp.params, nil
+	}
+
+	p, err := pc.fetch(ctx, providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.m[providerConfig] = cachedParams{params: p, fetched: pc.now()}
+	return p, nil
+}
+
+// A validator rejects ZeebeClusters whose parameters the Console API would
+// reject, or that would change their cluster in ways it does not support.
+type validator struct {
+	params  paramsGetter
+	decoder *admission.Decoder
+	log     logging.Logger
+}
+
+// Handle validates a ZeebeCluster that is created or updated.
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &v1alpha1.ZeebeCluster{}
+	if err := v.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// Never stand in the way of a ZeebeCluster that is being deleted, for
+	// example when its finalizer is removed.
+	if meta.WasDeleted(cr) {
+		return admission.Allowed("")
+	}
+
+	var old *v1alpha1.ZeebeCluster
+	if req.Operation == admissionv1.Update {
+		old = &v1alpha1.ZeebeCluster{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	errs := validateUpdate(old, cr)
+	var warnings []string
+	// Changes that are denied anyway need not be checked against what the
+	// Console API offers.
+	if p := cr.Spec.ForProvider; len(errs) == 0 && needsParams(old, p) {
+		params, err := v.params.Get(ctx, providerConfigName(cr))
+		if err != nil {
+			// Do not block changes while the Console API is unavailable;
+			// they will be rejected when the cluster is reconciled.
+			v.log.Debug(errCheckParams, "resource", cr.GetName(), "error", err)
+			warnings = append(warnings, fmt.Sprintf("%s: %s", errCheckParams, err))
+		} else {
+			errs = append(errs, validateParams(params, old, p)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// providerConfigName returns the name of the ProviderConfig the supplied
+// ZeebeCluster uses.
+func providerConfigName(mg resource.Managed) string {
+	if ref := mg.GetProviderConfigReference(); ref != nil && ref.Name != "" {
+		return ref.Name
+	}
+	return defaultProviderConfig
+}
+
+// needsParams returns true if the supplied parameters set a plan, channel,
+// generation or region, by ID or by name, and are new or change it.
+func needsParams(old *v1alpha1.ZeebeCluster, p v1alpha1.ZeebeClusterParameters) bool {
+	o := v1alpha1.ZeebeClusterParameters{}
+	if old != nil {
+		o = old.Spec.ForProvider
+	}
+	for _, c := range []param{
+		newParam("planId", p.PlanId, o.PlanId, "planName", p.PlanName, o.PlanName),
+		newParam("channelId", p.ChannelId, o.ChannelId, "channelName", p.ChannelName, o.ChannelName),
+		newParam("generationId", p.GenerationId, o.GenerationId, "generationName", p.GenerationName, o.GenerationName),
+		newParam("regionId", p.RegionId, o.RegionId, "region", p.Region, o.Region),
+	} {
+		if c.changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateUpdate rejects changes to immutable parameters and generation
+// downgrades. A ZeebeCluster that is created has no old version.
+func validateUpdate(old, cr *v1alpha1.ZeebeCluster) field.ErrorList {
+	if old == nil {
+		return nil
+	}
+	errs := field.ErrorList{}
+	path := field.NewPath("spec", "forProvider")
+	o, p := old.Spec.ForProvider, cr.Spec.ForProvider
+
+	// The region may be filled in once, when it is late initialized.
+	if o.Region != "" && p.Region != o.Region {
+		errs = append(errs, field.Invalid(path.Child("region"), p.Region, errImmutable))
+	}
+	if o.RegionId != "" && p.RegionId != o.RegionId {
+		errs = append(errs, field.Invalid(path.Child("regionId"), p.RegionId, errImmutable))
+	}
+
+	// The generation a cluster runs may lag behind the one it was asked to
+	// run, for example until its maintenance window opens.
+	if p.GenerationName != "" && p.GenerationName != o.GenerationName {
+		for _, current := range []string{o.GenerationName, old.Status.AtProvider.GenerationName} {
+			if isDowngrade(current, p.GenerationName) {
+				errs = append(errs, field.Forbidden(path.Child("generationName"), fmt.Sprintf(errDowngrade, current, p.GenerationName)))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// validateParams rejects plans, channels, generations and regions that are
+// not offered by the Console API, unless they did not change. Each is looked
+// up by its ID if set, or else by its name, the way it is when creating a
+// cluster.
+func validateParams(params *cc.ClusterParams, old *v1alpha1.ZeebeCluster, p v1alpha1.ZeebeClusterParameters) field.ErrorList {
+	o := v1alpha1.ZeebeClusterParameters{}
+	if old != nil {
+		o = old.Spec.ForProvider
+	}
+	errs := field.ErrorList{}
+
+	ch := newParam("channelId", p.ChannelId, o.ChannelId, "channelName", p.ChannelName, o.ChannelName)
+	channel := camunda.FindChannel(params, ch.value())
+	if ch.changed() && channel.Id == "" {
+		offered := make([]offer, 0, len(params.Channels))
+		for _, c := range params.Channels {
+			offered = append(offered, offer{id: c.Id, name: c.Name})
+		}
+		errs = append(errs, ch.notSupported(offered))
+	}
+
+	plan := newParam("planId", p.PlanId, o.PlanId, "planName", p.PlanName, o.PlanName)
+	if plan.changed() && camunda.FindPlan(params, plan.value()).Id == "" {
+		offered := make([]offer, 0, len(params.ClusterPlanTypes))
+		for _, pt := range params.ClusterPlanTypes {
+			offered = append(offered, offer{id: pt.Id, name: pt.Name})
+		}
+		errs = append(errs, plan.notSupported(offered))
+	}
+
+	// A generation must be allowed by its channel, so it is checked again
+	// when the channel changes.
+	gen := newParam("generationId", p.GenerationId, o.GenerationId, "generationName", p.GenerationName, o.GenerationName)
+	if gen.value() != "" && (gen.changed() || ch.changed()) && channel.Id != "" && camunda.FindGeneration(channel, gen.value()).Id == "" {
+		offered := make([]offer, 0, len(channel.AllowedGeneration))
+		for _, g := range channel.AllowedGeneration {
+			offered = append(offered, offer{id: g.Id, name: g.Name})
+		}
+		errs = append(errs, gen.notSupported(offered))
+	}
+
+	region := newParam("regionId", p.RegionId, o.RegionId, "region", p.Region, o.Region)
+	if region.changed() && camunda.FindRegion(params, region.value()).Id == "" {
+		offered := make([]offer, 0, len(params.Regions))
+		for _, r := range params.Regions {
+			offered = append(offered, offer{id: r.Id, name: r.Name})
+		}
+		errs = append(errs, region.notSupported(offered))
+	}
+	return errs
+}
+
+// An offer is a plan, channel, generation or region offered by the Console
+// API.
+type offer struct {
+	id   string
+	name string
+}
+
+// A param is a cluster parameter that may be set by ID or by name. The ID
+// takes precedence.
+type param struct {
+	idField, id, oldID       string
+	nameField, name, oldName string
+}
+
+func newParam(idField, id, oldID, nameField, name, oldName string) param {
+	return param{idField: idField, id: id, oldID: oldID, nameField: nameField, name: name, oldName: oldName}
+}
+
+// value returns the ID or name the parameter is looked up by.
+func (p param) value() string { return firstNonEmpty(p.id, p.name) }
+
+// changed returns true if the parameter is set, and is new or changed.
+func (p param) changed() bool {
+	return p.value() != "" && p.value() != firstNonEmpty(p.oldID, p.oldName)
+}
+
+// notSupported returns an error listing the IDs or names of the supplied
+// offers, matching the field the parameter was set by.
+func (p param) notSupported(offered []offer) *field.Error {
+	f, supported := p.nameField, make([]string, 0, len(offered))
+	for _, o := range offered {
+		supported = append(supported, o.name)
+	}
+	if p.id != "" {
+		f, supported = p.idField, supported[:0]
+		for _, o := range offered {
+			supported = append(supported, o.id)
+		}
+	}
+	return field.NotSupported(field.NewPath("spec", "forProvider", f), p.value(), supported)
+}
+
+// generationVersion matches the version in a generation name such as
+// "Zeebe 1.2.0-alpha1".
+var generationVersion = regexp.MustCompile(`\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?`)
+
+// isDowngrade returns true if moving from the current to the desired
+// generation would downgrade a cluster. Generations whose names do not
+// contain a version are never considered downgrades.
+func isDowngrade(current, desired string) bool {
+	c, err := version.ParseSemantic(generationVersion.FindString(current))
+	if err != nil {
+		return false
+	}
+	d, err := version.ParseSemantic(generationVersion.FindString(desired))
+	if err != nil {
+		return false
+	}
+	return d.LessThan(c)
+}
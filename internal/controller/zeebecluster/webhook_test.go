@@ -0,0 +1,377 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebecluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+var clusterParams = &cc.ClusterParams{
+	Channels: []cc.Channel{
+		{
+			Id:   "channel-stable",
+			Name: "Stable",
+			AllowedGeneration: []cc.Generation{
+				{Id: "generation-zeebe-1-0-0", Name: "Zeebe 1.0.0"},
+				{Id: "generation-zeebe-1-1-0", Name: "Zeebe 1.1.0"},
+				{Id: "generation-zeebe-latest", Name: "Zeebe Latest"},
+			},
+		},
+		{
+			Id:   "channel-alpha",
+			Name: "Alpha (Preview)",
+			AllowedGeneration: []cc.Generation{
+				{Id: "generation-zeebe-1-0-0", Name: "Zeebe 1.0.0"},
+				{Id: "generation-zeebe-1-2-0-alpha1", Name: "Zeebe 1.2.0-alpha1"},
+			},
+		},
+	},
+	ClusterPlanTypes: []cc.ClusterPlantType{{Id: "plan-development", Name: "Development"}, {Id: "plan-production-s", Name: "Production - S"}},
+	Regions:          []cc.Region{{Id: "region-europe-west-1d", Name: "Europe West 1D"}, {Id: "region-us-east-1b", Name: "US East 1B"}},
+}
+
+type paramsGetterFn func(ctx context.Context, providerConfig string) (*cc.ClusterParams, error)
+
+func (fn paramsGetterFn) Get(ctx context.Context, providerConfig string) (*cc.ClusterParams, error) {
+	return fn(ctx, providerConfig)
+}
+
+func withProviderConfig(name string) zeebeClusterModifier {
+	return func(cr *v1alpha1.ZeebeCluster) { cr.SetProviderConfigReference(&xpv1.Reference{Name: name}) }
+}
+
+func withDeletionTimestamp() zeebeClusterModifier {
+	return func(cr *v1alpha1.ZeebeCluster) {
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+	}
+}
+
+// raw returns the supplied ZeebeCluster the way the API server sends it.
+func raw(cr *v1alpha1.ZeebeCluster) runtime.RawExtension {
+	if cr == nil {
+		return runtime.RawExtension{}
+	}
+	cr.SetGroupVersionKind(v1alpha1.ZeebeClusterGroupVersionKind)
+	b, _ := json.Marshal(cr)
+	return runtime.RawExtension{Raw: b}
+}
+
+func TestHandle(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme(...): %v", err)
+	}
+	d, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatalf("NewDecoder(...): %v", err)
+	}
+
+	withParams := func(p v1alpha1.ZeebeClusterParameters, f func(p *v1alpha1.ZeebeClusterParameters)) v1alpha1.ZeebeClusterParameters {
+		f(&p)
+		return p
+	}
+
+	type want struct {
+		allowed  bool
+		code     int32
+		message  string
+		warnings []string
+	}
+
+	cases := map[string]struct {
+		reason    string
+		params    paramsGetter
+		operation admissionv1.Operation
+		old       *v1alpha1.ZeebeCluster
+		cr        *v1alpha1.ZeebeCluster
+		object    []byte
+		want      want
+	}{
+		"Undecodable": {
+			reason:    "Objects that are not ZeebeClusters should be rejected as bad requests.",
+			operation: admissionv1.Create,
+			object:    []byte("{"),
+			want:      want{code: http.StatusBadRequest},
+		},
+		"Deleted": {
+			reason:    "ZeebeClusters that are being deleted should always be allowed.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return nil, errBoom }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(params)),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.Region = "US East 1" })), withDeletionTimestamp()),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+		"CreateValid": {
+			reason: "ZeebeClusters whose channel and plan are offered should be allowed.",
+			params: paramsGetterFn(func(_ context.Context, pc string) (*cc.ClusterParams, error) {
+				if pc != "cool-config" {
+					return nil, errBoom
+				}
+				return clusterParams, nil
+			}),
+			operation: admissionv1.Create,
+			cr:        zeebeCluster(withParameters(params), withProviderConfig("cool-config")),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+		"CreateChannelByPartialName": {
+			reason:    "ZeebeClusters whose channel is named by a part of an offered channel's name should be allowed, as they are when creating a cluster.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Create,
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.ChannelName = "Alpha" }))),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+		"CreateChannelAndPlanByID": {
+			reason:    "ZeebeClusters whose channel and plan are named by their IDs should be allowed, as they are when creating a cluster.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Create,
+			cr: zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) {
+				p.ChannelName = "channel-alpha"
+				p.PlanName = "plan-production-s"
+			}))),
+			want: want{allowed: true, code: http.StatusOK},
+		},
+		"CreateUnknownChannelAndPlan": {
+			reason:    "ZeebeClusters whose channel or plan is not offered should be denied.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Create,
+			cr: zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) {
+				p.ChannelName = "Nightly"
+				p.PlanName = "Huge"
+			}))),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `[spec.forProvider.channelName: Unsupported value: "Nightly": supported values: "Stable", "Alpha (Preview)", spec.forProvider.planName: Unsupported value: "Huge": supported values: "Development", "Production - S"]`,
+			},
+		},
+		"CreateByID": {
+			reason:    "ZeebeClusters that identify their plan, channel, generation and region only by ID, as v1beta1 ones do, should be allowed if they are offered.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Create,
+			cr: zeebeCluster(withParameters(v1alpha1.ZeebeClusterParameters{
+				PlanId:       "plan-production-s",
+				ChannelId:    "channel-alpha",
+				GenerationId: "generation-zeebe-1-2-0-alpha1",
+				RegionId:     "region-us-east-1b",
+			})),
+			want: want{allowed: true, code: http.StatusOK},
+		},
+		"CreateUnknownIDs": {
+			reason:    "ZeebeClusters whose plan, channel or region ID is not offered should be denied, even if they also set an offered name.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Create,
+			cr: zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) {
+				p.PlanId = "plan-huge"
+				p.ChannelId = "channel-nightly"
+				p.RegionId = "region-moon-1a"
+			}))),
+			want: want{
+				code: http.StatusForbidden,
+				message: `[spec.forProvider.channelId: Unsupported value: "channel-nightly": supported values: "channel-stable", "channel-alpha", ` +
+					`spec.forProvider.planId: Unsupported value: "plan-huge": supported values: "plan-development", "plan-production-s", ` +
+					`spec.forProvider.regionId: Unsupported value: "region-moon-1a": supported values: "region-europe-west-1d", "region-us-east-1b"]`,
+			},
+		},
+		"CreateGenerationNotOnChannel": {
+			reason:    "ZeebeClusters whose generation is not allowed by their channel should be denied.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Create,
+			cr: zeebeCluster(withParameters(v1alpha1.ZeebeClusterParameters{
+				ChannelId:    "channel-alpha",
+				GenerationId: "generation-zeebe-1-1-0",
+			})),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `spec.forProvider.generationId: Unsupported value: "generation-zeebe-1-1-0": supported values: "generation-zeebe-1-0-0", "generation-zeebe-1-2-0-alpha1"`,
+			},
+		},
+		"UpdateUnknownGenerationID": {
+			reason:    "ZeebeClusters should not be allowed to move to a generation ID that is not offered.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(params)),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.GenerationId = "generation-zeebe-9-9-9" }))),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `spec.forProvider.generationId: Unsupported value: "generation-zeebe-9-9-9": supported values: "generation-zeebe-1-0-0", "generation-zeebe-1-1-0", "generation-zeebe-latest"`,
+			},
+		},
+		"CreateParamsUnavailable": {
+			reason:    "ZeebeClusters should be allowed with a warning if the offered channels and plans cannot be fetched.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return nil, errBoom }),
+			operation: admissionv1.Create,
+			cr:        zeebeCluster(withParameters(params)),
+			want:      want{allowed: true, code: http.StatusOK, warnings: []string{errCheckParams + ": boom"}},
+		},
+		"UpdateUnchangedChannel": {
+			reason:    "The channel and plan of a ZeebeCluster should not be checked again if they did not change.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return nil, errBoom }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.ChannelName = "Retired" }))),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.ChannelName = "Retired" }))),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+		"UpdateRegion": {
+			reason:    "The region of a ZeebeCluster should be immutable.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(params)),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.Region = "US East 1" }))),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `spec.forProvider.region: Invalid value: "US East 1": field is immutable`,
+			},
+		},
+		"UpdateRegionID": {
+			reason:    "The region ID of a ZeebeCluster should be immutable.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.RegionId = "region-europe-west-1d" }))),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.RegionId = "region-us-east-1b" }))),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `spec.forProvider.regionId: Invalid value: "region-us-east-1b": field is immutable`,
+			},
+		},
+		"LateInitializeRegion": {
+			reason:    "The region of a ZeebeCluster should be allowed to be set once.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.Region = "" }))),
+			cr:        zeebeCluster(withParameters(params)),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+		"UpgradeGeneration": {
+			reason:    "ZeebeClusters should be allowed to move to a newer generation.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(params)),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.GenerationName = "Zeebe 1.1.0" }))),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+		"DowngradeGeneration": {
+			reason:    "ZeebeClusters should not be allowed to move to an older generation.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(params)),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.GenerationName = "Zeebe 1.0.0-alpha1" }))),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `spec.forProvider.generationName: Forbidden: cannot downgrade from "Zeebe 1.0.0" to "Zeebe 1.0.0-alpha1"`,
+			},
+		},
+		"DowngradeRunningGeneration": {
+			reason:    "ZeebeClusters should not be allowed to move to a generation older than the one their cluster runs.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old: zeebeCluster(
+				withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.GenerationName = "Zeebe 0.26.0" })),
+				withObservation(v1alpha1.ZeebeClusterObservation{GenerationName: "Zeebe 1.1.0"}),
+			),
+			cr: zeebeCluster(withParameters(params)),
+			want: want{
+				code:    http.StatusForbidden,
+				message: `spec.forProvider.generationName: Forbidden: cannot downgrade from "Zeebe 1.1.0" to "Zeebe 1.0.0"`,
+			},
+		},
+		"UnversionedGeneration": {
+			reason:    "Generations whose names contain no version should not be considered downgrades.",
+			params:    paramsGetterFn(func(context.Context, string) (*cc.ClusterParams, error) { return clusterParams, nil }),
+			operation: admissionv1.Update,
+			old:       zeebeCluster(withParameters(params)),
+			cr:        zeebeCluster(withParameters(withParams(params, func(p *v1alpha1.ZeebeClusterParameters) { p.GenerationName = "Zeebe Latest" }))),
+			want:      want{allowed: true, code: http.StatusOK},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{params: tc.params, decoder: d, log: logging.NewNopLogger()}
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Object:    raw(tc.cr),
+				OldObject: raw(tc.old),
+			}}
+			if tc.object != nil {
+				req.Object = runtime.RawExtension{Raw: tc.object}
+			}
+
+			r := v.Handle(context.Background(), req)
+			got := want{allowed: r.Allowed, code: r.Result.Code, message: string(r.Result.Reason), warnings: r.Warnings}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParamsCache(t *testing.T) {
+	fetches := 0
+	fail := false
+	now := time.Now()
+	pc := newParamsCache(func(_ context.Context, _ string) (*cc.ClusterParams, error) {
+		fetches++
+		if fail {
+			return nil, errBoom
+		}
+		return clusterParams, nil
+	}, time.Minute)
+	pc.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	steps := []struct {
+		reason  string
+		advance time.Duration
+		fail    bool
+		err     error
+		fetches int
+	}{
+		{reason: "Parameters that are not cached should be fetched.", fetches: 1},
+		{reason: "Cached parameters should be returned while they are fresh.", advance: 30 * time.Second, fetches: 1},
+		{reason: "Stale parameters should be fetched again.", advance: time.Minute, fetches: 2},
+		{reason: "Errors should be returned when stale parameters cannot be fetched.", advance: time.Minute, fail: true, err: errBoom, fetches: 3},
+		{reason: "Errors should not be cached.", err: errBoom, fail: true, fetches: 4},
+	}
+	for _, s := range steps {
+		now = now.Add(s.advance)
+		fail = s.fail
+		_, err := pc.Get(ctx, "default")
+		if diff := cmp.Diff(s.err, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\n%s\nGet(...): -want error, +got error:\n%s\n", s.reason, diff)
+		}
+		if fetches != s.fetches {
+			t.Errorf("\n%s\nGet(...): want %d fetches, got %d", s.reason, s.fetches, fetches)
+		}
+	}
+}
@@ -18,77 +18,108 @@ package zeebecluster
 
 import (
 	"context"
-	"fmt"
+	"time"
+
 	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
-	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/trace/jaeger"
-	"go.opentelemetry.io/otel/semconv"
 	"go.opentelemetry.io/otel/trace"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	sdktraceresource "go.opentelemetry.io/otel/sdk/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
-	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
 	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/options"
+	"github.com/salaboy/provider-camunda-cloud/internal/pause"
+	"github.com/salaboy/provider-camunda-cloud/internal/tracing"
 )
 
 const (
-	errNotMyType    = "managed resource is not a MyType custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-	errCannotLoginToCC = "cannot login to Camunda Cloud"
-	errNewClient = "cannot create new Service"
+	errNotMyType            = "managed resource is not a MyType custom resource"
+	errTrackPCUsage         = "cannot track ProviderConfig usage"
+	errGetPC                = "cannot get ProviderConfig"
+	errGetCreds             = "cannot get credentials"
+	errCannotLoginToCC      = "cannot login to Camunda Cloud"
+	errNewClient            = "cannot create new Service"
+	errExchangeToken        = "cannot exchange injected identity for a Camunda access token"
+	errNewHTTPClient        = "cannot configure HTTP transport"
+	errValidateOrganization = "cannot validate Camunda Cloud organization of credentials"
+	errOrganizationChanged  = "cluster belongs to organization %q but the ProviderConfig's credentials belong to organization %q"
+	errRegisterMetrics      = "cannot register ZeebeCluster metrics"
+	errIndexSecrets         = "cannot index ProviderConfigs by the Secrets they reference"
+	errObserveOnlyNotFound  = "cluster %q does not exist and cannot be created because the ZeebeCluster is observe-only"
+	errObserveOnly          = "refusing to create a cluster for an observe-only ZeebeCluster"
+	errDeletionProtected    = "refusing to delete a cluster with deletion protection enabled"
+	errScheduleChanges      = "cannot schedule cluster changes"
 )
 
-type CCCredentials struct{
+type CCCredentials struct {
 	CCClientId string `json:"ccClientId"`
 	CCSecretId string `json:"ccSecretId"`
 }
 
-
 // Setup adds a controller that reconciles MyType managed resources.
-func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o options.Options) error {
 	name := managed.ControllerName(v1alpha1.ZeebeClusterGroupKind)
 
-	o := controller.Options{
-		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
-	}
+	tokens := camunda.NewTokens()
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ZeebeClusterGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			kube:      mgr.GetClient(),
+			usage:     resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			throttles: camunda.NewThrottles(),
+			tokens:    tokens,
+			log:       l.WithValues("controller", name),
+			recorder:  recorder,
 		}),
+		managed.WithPollInterval(o.PollInterval),
 		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		managed.WithRecorder(recorder))
+
+	if err := registerClusterCollector(metrics.Registry, mgr.GetClient(), l.WithValues("controller", name)); err != nil {
+		return errors.Wrap(err, errRegisterMetrics)
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apisv1alpha1.ProviderConfig{}, secretIndex, indexSecretRefs); err != nil {
+		return errors.Wrap(err, errIndexSecrets)
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha1.ZeebeCluster{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(
+			requestsForSecret(mgr.GetClient(), l.WithValues("controller", name), tokens, v1alpha1.ZeebeClusterKind))).
+		Complete(pause.NewReconciler(mgr, resource.ManagedKind(v1alpha1.ZeebeClusterGroupVersionKind),
+			tracing.NewReconciler(v1alpha1.ZeebeClusterKind, &throttledReconciler{wrapped: r, log: l.WithValues("controller", name)}), pause.WithLogger(l.WithValues("controller", name))))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
+	kube      client.Client
+	usage     resource.Tracker
+	throttles *camunda.Throttles
+	tokens    *camunda.Tokens
+	log       logging.Logger
+	recorder  event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -102,6 +133,7 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if !ok {
 		return nil, errors.New(errNotMyType)
 	}
+	ctx = reconcileContext(ctx, cr)
 
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
@@ -112,60 +144,118 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	log := c.log.WithValues("resource", cr.GetName(), "provider-config", pc.GetName())
+
+	svc, err := c.login(ctx, pc, log)
 	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+		setThrottled(cr, err)
+		return nil, err
 	}
 
-	credentials := 	CCCredentials{}
-
-	err = json.Unmarshal(data, &credentials)
+	org, err := svc.ValidateOrganization(pc.Spec.OrganizationID)
 	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+		return nil, errors.Wrap(err, errValidateOrganization)
+	}
+	if observed := cr.Status.AtProvider.OrganizationId; observed != "" && org != "" && observed != org {
+		return nil, errors.Errorf(errOrganizationChanged, observed, org)
 	}
 
-	var svc = cc.CCClient{}
-
-	flush := svc.InitTracer()
-	defer flush()
-	var logged, _ = svc.LoginWithContext(ctx, credentials.CCClientId, credentials.CCSecretId)
-
-	flush2 := initTracer()
-	defer flush2()
+	return &external{service: svc, tracer: otel.Tracer(tracing.TracerName), log: log, recorder: c.recorder, organizationID: org}, nil
+}
 
-	if logged{
-		fmt.Printf("logged in!\n")
-	}else{
-		return nil, errors.New(errCannotLoginToCC)
+// Login returns a Console API client for the named ProviderConfig, loading
+// its credentials the way ZeebeClusters connect, and the Camunda Cloud
+// organization the credentials belong to. It does not share the controller's
+// token cache, so it is meant for tools outside the provider.
+func Login(ctx context.Context, kube client.Client, providerConfig string, l logging.Logger) (*camunda.Client, string, error) {
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: providerConfig}, pc); err != nil {
+		return nil, "", errors.Wrap(err, errGetPC)
 	}
+	c := &connector{
+		kube:      kube,
+		throttles: camunda.NewThrottles(),
+		tokens:    camunda.NewTokens(),
+		log:       l,
+		recorder:  event.NewNopRecorder(),
+	}
+	svc, err := c.login(ctx, pc, l.WithValues("provider-config", providerConfig))
+	if err != nil {
+		return nil, "", err
+	}
+	org, err := svc.ValidateOrganization(pc.Spec.OrganizationID)
+	return svc, org, errors.Wrap(err, errValidateOrganization)
+}
 
+// login returns a Console API client for the supplied ProviderConfig. Its
+// access token is taken from the token cache if possible.
+func (c *connector) login(ctx context.Context, pc *apisv1alpha1.ProviderConfig, log logging.Logger) (*camunda.Client, error) {
+	hc, err := camunda.NewHTTPClient(ctx, c.kube, pc.GetName(), pc.Spec.Transport)
 	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
+		return nil, errors.Wrap(err, errNewHTTPClient)
 	}
 
+	var svc *camunda.Client
+	o := []camunda.Option{
+		camunda.WithHTTPClient(hc),
+		camunda.WithThrottle(c.throttles.Get(pc.GetName(), pc.Spec.RateLimit)),
+		camunda.WithUnauthorizedHandler(func() { c.tokens.Invalidate(pc.GetName()) }),
+	}
+	if ep := pc.Spec.Endpoints; ep != nil {
+		o = append(o, camunda.WithEndpoints(ep.Login, ep.API))
+	}
 
-	return &external{service: svc, tracer: otel.Tracer("provider-camunda-cloud")}, nil
-}
+	var fp camunda.Fingerprint
+	cd := pc.Spec.Credentials
+	switch cd.Source { //nolint:exhaustive
+	case xpv1.CredentialsSourceInjectedIdentity:
+		te, _ := json.Marshal(cd.TokenExchange)
+		fp = camunda.FingerprintOf(te)
+		if token, ok := c.tokens.Get(pc.GetName(), fp); ok {
+			svc = camunda.New(append(o, camunda.WithAccessToken(token))...)
+			break
+		}
 
-func initTracer() func() {
-
-	// Create and install Jaeger export pipeline.
-	flush, err := jaeger.InstallNewPipeline(
-		jaeger.WithCollectorEndpoint("http://localhost:14268/api/traces"),
-		jaeger.WithSDKOptions(
-			sdktrace.WithSampler(sdktrace.AlwaysSample()),
-			sdktrace.WithResource(sdktraceresource.NewWithAttributes(
-				semconv.ServiceNameKey.String("provider-camunda-cloud"),
-				attribute.String("exporter", "jaeger"),
-				attribute.Float64("float", 312.23),
-			)),
-		),
-	)
-	if err != nil {
-		fmt.Printf("Failed to initialize Tracer %s",err)
+		token, err := camunda.ExchangeToken(ctx, hc, cd.TokenExchange)
+		if err != nil {
+			return nil, errors.Wrap(err, errExchangeToken)
+		}
+		svc = camunda.New(append(o, camunda.WithAccessToken(token))...)
+	default:
+		data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetCreds)
+		}
+		fp = camunda.FingerprintOf(data)
+		if token, ok := c.tokens.Get(pc.GetName(), fp); ok {
+			svc = camunda.New(append(o, camunda.WithAccessToken(token))...)
+			break
+		}
+
+		credentials := CCCredentials{}
+
+		err = json.Unmarshal(data, &credentials)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetCreds)
+		}
+
+		svc = camunda.New(o...)
+		if _, err := svc.LoginWithContext(ctx, credentials.CCClientId, credentials.CCSecretId); err != nil {
+			return nil, errors.Wrap(err, errCannotLoginToCC)
+		}
+		log.Debug("Logged in to Camunda Cloud")
 	}
-	return flush
+	token, expiry := svc.AccessToken()
+	c.tokens.Set(pc.GetName(), fp, token, expiry)
+
+	return svc, nil
+}
+
+// reconcileContext returns a context whose spans nest under the in-flight
+// reconcile of the supplied resource. The managed reconciler does not pass its
+// own context on to the external client.
+func reconcileContext(ctx context.Context, cr *v1alpha1.ZeebeCluster) context.Context {
+	return tracing.ContextWithReconcile(ctx, v1alpha1.ZeebeClusterKind, types.NamespacedName{Name: cr.GetName()})
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -173,67 +263,119 @@ func initTracer() func() {
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service cc.CCClient
-	tracer  trace.Tracer
+	service  camunda.ClusterClient
+	tracer   trace.Tracer
+	log      logging.Logger
+	recorder event.Recorder
+
+	// organizationID is the Camunda Cloud organization of the credentials
+	// the service was created with, if known.
+	organizationID string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 
-	ctx, span := e.tracer.Start(ctx, "observe")
-	defer span.End()
-
 	cr, ok := mg.(*v1alpha1.ZeebeCluster)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotMyType)
 	}
 
-	// These fmt statements should be removed in the real implementation.
-	fmt.Printf("Observing: %+v\n", cr)
+	ctx, span := e.tracer.Start(reconcileContext(ctx, cr), "observe")
+	defer span.End()
 
+	// A deletion that was blocked is no longer once deletion protection is
+	// disabled, whether or not the cluster is being deleted.
+	if !cr.Spec.ForProvider.DeletionProtection && cr.GetCondition(v1alpha1.TypeDeletionBlocked).Status == corev1.ConditionTrue {
+		cr.SetConditions(v1alpha1.DeletionAllowed())
+	}
 
-	fmt.Printf("Querying for Cluster Name: %s\n", cr.Name)
-	existing, err := e.service.GetClusterByNameWithContext(ctx, cr.Name)
+	observeOnly := cr.Spec.ManagementPolicy == v1alpha1.ManagementObserveOnly
+	if observeOnly && meta.WasDeleted(cr) {
+		// Pretend the cluster is gone, so that the managed reconciler
+		// releases the ZeebeCluster without deleting the cluster.
+		e.log.Debug("Releasing observe-only cluster", "cluster-id", cr.Status.AtProvider.ClusterId)
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 
-	fmt.Printf("Existing Cluster ID: %s\n", existing.ID)
-	//if database.IsNotFound(err) {
+	existing, err := e.service.GetClusterByNameWithContext(ctx, cr.Name)
+	setThrottled(cr, err)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, err
 	}
+	e.log.Debug("Observed cluster by name", "cluster-id", existing.ID)
 	if existing.ID == "" && cr.Status.AtProvider.ClusterId == "" {
+		if observeOnly {
+			return managed.ExternalObservation{}, errors.Errorf(errObserveOnlyNotFound, cr.GetName())
+		}
 		return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}}, nil
 	} else {
 
-		if cr.Spec.ForProvider.PlanName != existing.ClusterPlantType.Name{
-			cr.Spec.ForProvider.PlanName = existing.ClusterPlantType.Name
-		}
+		lateInitialized := lateInitialize(&cr.Spec.ForProvider, existing)
 
-		if cr.Spec.ForProvider.GenerationName != existing.Generation.Name{
-			cr.Spec.ForProvider.GenerationName = existing.Generation.Name
-		}
+		// A cluster that was just created may not be listed yet, so look it
+		// up by the ID it was created with.
+		id := firstNonEmpty(existing.ID, cr.Status.AtProvider.ClusterId)
 
-		if cr.Spec.ForProvider.ChannelName != existing.Channel.Name {
-			cr.Spec.ForProvider.ChannelName = existing.Channel.Name
+		before := cr.Status.AtProvider
+		cr.Status.AtProvider.ClusterId = id
+		cr.Status.AtProvider.PlanName = existing.ClusterPlantType.Name
+		cr.Status.AtProvider.GenerationName = existing.Generation.Name
+		cr.Status.AtProvider.ChannelName = existing.Channel.Name
+		if e.organizationID != "" {
+			cr.Status.AtProvider.OrganizationId = e.organizationID
 		}
-		if cr.Spec.ForProvider.Region != existing.K8sContext.Name{
-			cr.Spec.ForProvider.Region = existing.K8sContext.Name
+		details, err := e.service.GetClusterDetailsWithContext(ctx, id)
+		setThrottled(cr, err)
+		if camunda.IsThrottled(err) {
+			return managed.ExternalObservation{}, err
 		}
-
-		cr.Status.AtProvider.ClusterId = existing.ID
-		clusterStatus, err := e.service.GetClusterDetailsWithContext(ctx, existing.ID)
 		if err != nil {
 			cr.SetConditions(xpv1.Unavailable())
-			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ConnectionDetails: managed.ConnectionDetails{}}, nil
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: observeOnly, ResourceLateInitialized: lateInitialized, ConnectionDetails: managed.ConnectionDetails{}}, nil
+		}
+		if details.Status.Ready == camunda.ClusterStatusNotFound {
+			e.log.Debug("Cluster no longer exists", "cluster-id", id)
+			if observeOnly {
+				return managed.ExternalObservation{}, errors.Errorf(errObserveOnlyNotFound, cr.GetName())
+			}
+			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}}, nil
 		}
-		cr.Status.AtProvider.ClusterStatus = clusterStatus
-		fmt.Printf("CLUSTER STATUS: %s\n", cr.Status.AtProvider.ClusterStatus.Ready)
+		clusterStatus := details.Status
+		cr.Status.AtProvider.ClusterStatus = observedStatus(clusterStatus)
+		if details.ID != "" {
+			setObservation(&cr.Status.AtProvider, details)
+		}
+		cr.SetConditions(
+			v1alpha1.ComponentReady(v1alpha1.TypeZeebeReady, clusterStatus.ZeebeStatus),
+			v1alpha1.ComponentReady(v1alpha1.TypeOperateReady, clusterStatus.OperateStatus),
+			v1alpha1.ComponentReady(v1alpha1.TypeTasklistReady, clusterStatus.TaskListStatus),
+			v1alpha1.ComponentReady(v1alpha1.TypeOptimizeReady, clusterStatus.OptimizeStatus),
+		)
+		recordChanges(e.recorder, cr, before, cr.Status.AtProvider)
+		e.log.Debug("Observed cluster status", "cluster-id", id, "ready", clusterStatus.Ready)
 		switch cr.Status.AtProvider.ClusterStatus.Ready {
 		case "Healthy":
+			observeHealthy(cr)
 			cr.SetConditions(xpv1.Available())
 		case "Creating":
 			cr.SetConditions(xpv1.Creating())
 		case "Not Healthy":
 			cr.SetConditions(xpv1.Unavailable())
 		}
+
+		// Changes are reported as pending until they are made. Disruptive
+		// changes that must wait for the maintenance window do not make the
+		// cluster out of date.
+		cs := changes(cr.Spec.ForProvider, cr.Status.AtProvider)
+		due, _, next, err := schedule(cs, cr.Spec.ForProvider.MaintenanceWindow, time.Now())
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errScheduleChanges)
+		}
+		setPending(cr, cs, next)
+		if (observeOnly || len(due) == 0) && cr.GetCondition(v1alpha1.TypeChangesDue).Status == corev1.ConditionTrue {
+			cr.SetConditions(v1alpha1.NoChangesDue())
+		}
+
 		return managed.ExternalObservation{
 			// Return false when the external resource does not exist. This lets
 			// the managed resource reconciler know that it needs to call Create to
@@ -243,54 +385,50 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 			// Return false when the external resource exists, but it not up to date
 			// with the desired managed resource state. This lets the managed
 			// resource reconciler know that it needs to call Update.
-			ResourceUpToDate: true,
+			ResourceUpToDate: observeOnly || len(due) == 0,
+
+			// Return true when spec fields that were not set were filled in
+			// from the external resource.
+			ResourceLateInitialized: lateInitialized,
 
 			// Return any details that may be required to connect to the external
 			// resource. These will be stored as the connection secret.
-			ConnectionDetails: managed.ConnectionDetails{},
+			ConnectionDetails: connectionDetails(cr.Status.AtProvider),
 		}, nil
 	}
-
-	return managed.ExternalObservation{
-		// Return false when the external resource does not exist. This lets
-		// the managed resource reconciler know that it needs to call Create to
-		// (re)create the resource, or that it has successfully been deleted.
-		ResourceExists: true,
-
-		// Return false when the external resource exists, but it not up to date
-		// with the desired managed resource state. This lets the managed
-		// resource reconciler know that it needs to call Update.
-		ResourceUpToDate: true,
-
-		// Return any details that may be required to connect to the external
-		// resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
 }
 
 func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 
-	ctx, span := e.tracer.Start(ctx, "create")
-	defer span.End()
-
 	cr, ok := mg.(*v1alpha1.ZeebeCluster)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotMyType)
 	}
 
-	fmt.Printf("Creating: %+v\n", cr)
+	ctx, span := e.tracer.Start(reconcileContext(ctx, cr), "create")
+	defer span.End()
+
+	if cr.Spec.ManagementPolicy == v1alpha1.ManagementObserveOnly {
+		return managed.ExternalCreation{}, errors.New(errObserveOnly)
+	}
 
-	e.service.GetClusterParamsWithContext(ctx)
+	// The client looks parameters up by ID or name, and IDs take precedence.
+	p := cr.Spec.ForProvider
+	plan, channel := firstNonEmpty(p.PlanId, p.PlanName), firstNonEmpty(p.ChannelId, p.ChannelName)
+	generation, region := firstNonEmpty(p.GenerationId, p.GenerationName), firstNonEmpty(p.RegionId, p.Region)
+	e.log.Debug("Creating cluster", "plan", plan, "channel", channel, "generation", generation, "region", region)
 
-	clusterId, err := e.service.CreateClusterWithParamsAndContext(ctx, mg.GetName(), cr.Spec.ForProvider.PlanName,
-		cr.Spec.ForProvider.ChannelName, cr.Spec.ForProvider.GenerationName, cr.Spec.ForProvider.Region)
+	clusterId, err := e.service.CreateClusterWithParamsAndContext(ctx, mg.GetName(), plan, channel, generation, region)
+	setThrottled(cr, err)
 	if err != nil {
-		fmt.Errorf("failed to create zeebe cluster %s\n", err.Error())
 		return managed.ExternalCreation{}, err
 	}
-	fmt.Printf("Updating Zeebe Cluster with ClusterId: %s\n", clusterId)
+	e.log.Info("Created cluster", "cluster-id", clusterId)
 
 	cr.Status.AtProvider.ClusterId = clusterId
+	if e.organizationID != "" {
+		cr.Status.AtProvider.OrganizationId = e.organizationID
+	}
 
 	cr.SetConditions(xpv1.Creating())
 
@@ -302,15 +440,42 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	ctx, span := e.tracer.Start(ctx, "update")
-	defer span.End()
-
 	cr, ok := mg.(*v1alpha1.ZeebeCluster)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotMyType)
 	}
 
-	fmt.Printf("Updating: %+v", cr)
+	ctx, span := e.tracer.Start(reconcileContext(ctx, cr), "update")
+	defer span.End()
+
+	if cr.Spec.ManagementPolicy == v1alpha1.ManagementObserveOnly {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	cs := changes(cr.Spec.ForProvider, cr.Status.AtProvider)
+	due, deferred, next, err := schedule(cs, cr.Spec.ForProvider.MaintenanceWindow, time.Now())
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errScheduleChanges)
+	}
+	setPending(cr, cs, next)
+
+	log := e.log.WithValues("cluster-id", cr.Status.AtProvider.ClusterId)
+	if len(deferred) > 0 {
+		log.Debug("Deferring disruptive changes to the next maintenance window", "changes", describe(deferred), "next-window", next)
+	}
+	if len(due) == 0 {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	// The Console API cannot change existing clusters, so changes that are
+	// due are left to an operator. They are reported once, rather than on
+	// every poll while they remain due.
+	c := v1alpha1.ChangesDue(describe(due))
+	if !cr.GetCondition(v1alpha1.TypeChangesDue).Equal(c) {
+		log.Info("Cluster changes are due", "changes", describe(due))
+		e.recorder.Event(cr, event.Warning(reasonChangesDue, errors.New(c.Message)))
+	}
+	cr.SetConditions(c)
 
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
@@ -320,23 +485,169 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	ctx, span := e.tracer.Start(ctx, "delete")
-	defer span.End()
-
 	cr, ok := mg.(*v1alpha1.ZeebeCluster)
 	if !ok {
 		return errors.New(errNotMyType)
 	}
 
-	fmt.Printf("Deleting: %+v", cr)
+	ctx, span := e.tracer.Start(reconcileContext(ctx, cr), "delete")
+	defer span.End()
+
+	log := e.log.WithValues("cluster-id", cr.Status.AtProvider.ClusterId)
+	if cr.Spec.ManagementPolicy == v1alpha1.ManagementObserveOnly {
+		log.Debug("Not deleting observe-only cluster")
+		return nil
+	}
+	if cr.Spec.ForProvider.DeletionProtection {
+		cr.SetConditions(v1alpha1.DeletionBlocked())
+		return errors.New(errDeletionProtected)
+	}
+	log.Debug("Deleting cluster")
 
 	deleted, err := e.service.DeleteClusterWithContext(ctx, cr.Status.AtProvider.ClusterId)
+	setThrottled(cr, err)
+	if camunda.IsThrottled(err) {
+		return err
+	}
 	if err != nil {
-		fmt.Printf("Failed to delete cluster: cluster not found %s", err)
+		log.Info("Cannot delete cluster, assuming it no longer exists", "error", err)
 	}
 	if deleted {
-		fmt.Printf("Cluster in camunda cloud deleted: %s ", cr.Status.AtProvider.ClusterId)
+		log.Info("Deleted cluster")
 	}
 
 	return nil
 }
+
+// lateInitialize fills in the parameters that are set by neither name nor ID
+// from the supplied cluster. It returns true if any were filled in.
+func lateInitialize(p *v1alpha1.ZeebeClusterParameters, cl cc.Cluster) bool {
+	li := false
+	for _, f := range []struct {
+		param    *string
+		id       string
+		observed string
+	}{
+		{param: &p.PlanName, id: p.PlanId, observed: cl.ClusterPlantType.Name},
+		{param: &p.GenerationName, id: p.GenerationId, observed: cl.Generation.Name},
+		{param: &p.ChannelName, id: p.ChannelId, observed: cl.Channel.Name},
+		{param: &p.Region, id: p.RegionId, observed: cl.K8sContext.Name},
+	} {
+		if *f.param == "" && f.id == "" && f.observed != "" {
+			*f.param = f.observed
+			li = true
+		}
+	}
+	return li
+}
+
+// setPending records the supplied changes as pending, and when the next
+// maintenance window opens if any of them wait for it.
+func setPending(cr *v1alpha1.ZeebeCluster, cs []change, next *time.Time) {
+	cr.Status.AtProvider.PendingChanges = describe(cs)
+	cr.Status.AtProvider.NextMaintenanceWindow = nil
+	if next != nil {
+		t := metav1.NewTime(*next)
+		cr.Status.AtProvider.NextMaintenanceWindow = &t
+	}
+}
+
+// setThrottled records on the supplied resource whether the supplied error was
+// caused by the Console API throttling requests, so that quota problems can be
+// told apart from real failures, and when the reconcile should be retried.
+func setThrottled(cr *v1alpha1.ZeebeCluster, err error) {
+	if at, ok := camunda.RetryAt(err); ok {
+		cr.SetConditions(v1alpha1.Throttled())
+		retries.Store(cr.GetName(), at)
+		return
+	}
+	if cr.GetCondition(v1alpha1.TypeThrottled).Status == corev1.ConditionTrue {
+		cr.SetConditions(v1alpha1.NotThrottled())
+	}
+}
+
+// observedStatus maps the status of a cluster reported by the Console API to
+// the status of a ZeebeCluster, so that the schema of the latter does not
+// follow changes to the client's types.
+func observedStatus(s camunda.ClusterStatus) v1alpha1.ClusterStatus {
+	return v1alpha1.ClusterStatus{
+		Ready:          s.Ready,
+		ZeebeStatus:    s.ZeebeStatus,
+		ZeebeURL:       s.ZeebeURL,
+		OperateStatus:  s.OperateStatus,
+		OperateURL:     s.OperateURL,
+		TaskListStatus: s.TaskListStatus,
+		TaskListURL:    s.TaskListURL,
+		OptimizeStatus: s.OptimizeStatus,
+		OptimizeURL:    s.OptimizeURL,
+	}
+}
+
+// setObservation updates the supplied observation with the supplied details of
+// a cluster, so that they are available without calling the Console API.
+func setObservation(o *v1alpha1.ZeebeClusterObservation, d camunda.ClusterDetails) {
+	o.PlanId = d.ClusterPlantType.Id
+	o.PlanName = d.ClusterPlantType.Name
+	o.ChannelId = d.Channel.Id
+	o.ChannelName = d.Channel.Name
+	o.GenerationId = d.Generation.Id
+	o.GenerationName = d.Generation.Name
+	o.RegionId = d.K8sContext.ID
+	o.RegionName = d.K8sContext.Name
+
+	o.ZeebeAddress = firstNonEmpty(d.Links.Zeebe, d.Status.ZeebeURL)
+	o.OperateURL = firstNonEmpty(d.Links.Operate, d.Status.OperateURL)
+	o.TasklistURL = firstNonEmpty(d.Links.Tasklist, d.Status.TaskListURL)
+	o.OptimizeURL = firstNonEmpty(d.Links.Optimize, d.Status.OptimizeURL)
+
+	o.Created = parseTime(d.Created)
+	o.LastUpgrade = parseTime(d.LastUpgrade)
+	o.Owner = firstNonEmpty(d.Owner.Name, d.Owner.ID)
+}
+
+// Keys of the connection details of a ZeebeCluster.
+const (
+	keyClusterID    = "clusterId"
+	keyZeebeAddress = "zeebeAddress"
+	keyOperateURL   = "operateUrl"
+	keyTasklistURL  = "tasklistUrl"
+	keyOptimizeURL  = "optimizeUrl"
+)
+
+// connectionDetails returns the connection details of the supplied observed
+// cluster. Details that were not observed are omitted.
+func connectionDetails(o v1alpha1.ZeebeClusterObservation) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{}
+	for k, v := range map[string]string{
+		keyClusterID:    o.ClusterId,
+		keyZeebeAddress: o.ZeebeAddress,
+		keyOperateURL:   o.OperateURL,
+		keyTasklistURL:  o.TasklistURL,
+		keyOptimizeURL:  o.OptimizeURL,
+	} {
+		if v != "" {
+			cd[k] = []byte(v)
+		}
+	}
+	return cd
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseTime parses an RFC 3339 timestamp reported by the Console API. It
+// returns nil if the timestamp is empty or malformed.
+func parseTime(s string) *metav1.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	mt := metav1.NewTime(t)
+	return &mt
+}
@@ -18,14 +18,30 @@ package zeebecluster
 
 import (
 	"context"
-	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda/fake"
+	"github.com/salaboy/provider-camunda-cloud/internal/fake/console"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -36,40 +52,999 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service interface{}
+const (
+	clusterName  = "cool"
+	clusterID    = "cool-id"
+	zeebeAddress = "cool-id.bru-2.zeebe.camunda.io:443"
+)
+
+var (
+	errBoom = errors.New("boom")
+
+	params = v1alpha1.ZeebeClusterParameters{
+		PlanName:       "Development",
+		ChannelName:    "Stable",
+		GenerationName: "Zeebe 1.0.0",
+		Region:         "Europe West 1D",
+	}
+
+	cluster = cc.Cluster{
+		ID:               clusterID,
+		Name:             clusterName,
+		ClusterPlantType: cc.ClusterPlantType{Name: "Development"},
+		Channel:          cc.Channel{Name: "Stable"},
+		Generation:       cc.Generation{Name: "Zeebe 1.0.0"},
+		K8sContext:       cc.K8sContext{Name: "Europe West 1D"},
+	}
+)
+
+type zeebeClusterModifier func(cr *v1alpha1.ZeebeCluster)
+
+func withParameters(p v1alpha1.ZeebeClusterParameters) zeebeClusterModifier {
+	return func(cr *v1alpha1.ZeebeCluster) { cr.Spec.ForProvider = p }
+}
+
+func withManagementPolicy(p v1alpha1.ManagementPolicy) zeebeClusterModifier {
+	return func(cr *v1alpha1.ZeebeCluster) { cr.Spec.ManagementPolicy = p }
+}
+
+func withObservation(o v1alpha1.ZeebeClusterObservation) zeebeClusterModifier {
+	return func(cr *v1alpha1.ZeebeCluster) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) zeebeClusterModifier {
+	return func(cr *v1alpha1.ZeebeCluster) { cr.SetConditions(c...) }
+}
+
+func zeebeCluster(m ...zeebeClusterModifier) *v1alpha1.ZeebeCluster {
+	cr := &v1alpha1.ZeebeCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName}}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// details returns the details of a cluster whose components all report the
+// supplied status.
+func details(ready, component string) camunda.ClusterDetails {
+	return camunda.ClusterDetails{
+		Cluster: cluster,
+		Status: camunda.ClusterStatus{
+			ClusterStatus: cc.ClusterStatus{
+				Ready:          ready,
+				ZeebeStatus:    component,
+				OperateStatus:  component,
+				TaskListStatus: component,
+			},
+			OptimizeStatus: component,
+		},
+		Links: camunda.ClusterLinks{Zeebe: zeebeAddress},
+	}
+}
+
+// observation returns the observation of a cluster with the supplied details.
+func observation(d camunda.ClusterDetails) v1alpha1.ZeebeClusterObservation {
+	return v1alpha1.ZeebeClusterObservation{
+		ClusterId: clusterID,
+		ClusterStatus: v1alpha1.ClusterStatus{
+			Ready:          d.Status.Ready,
+			ZeebeStatus:    d.Status.ZeebeStatus,
+			OperateStatus:  d.Status.OperateStatus,
+			TaskListStatus: d.Status.TaskListStatus,
+			OptimizeStatus: d.Status.OptimizeStatus,
+		},
+		PlanName:       "Development",
+		ChannelName:    "Stable",
+		GenerationName: "Zeebe 1.0.0",
+		RegionName:     "Europe West 1D",
+		ZeebeAddress:   zeebeAddress,
+	}
+}
+
+// components returns the conditions of components that all report the
+// supplied status.
+func components(status string) []xpv1.Condition {
+	return []xpv1.Condition{
+		v1alpha1.ComponentReady(v1alpha1.TypeZeebeReady, status),
+		v1alpha1.ComponentReady(v1alpha1.TypeOperateReady, status),
+		v1alpha1.ComponentReady(v1alpha1.TypeTasklistReady, status),
+		v1alpha1.ComponentReady(v1alpha1.TypeOptimizeReady, status),
 	}
+}
 
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+func newExternal(service camunda.ClusterClient) *external {
+	return &external{
+		service:  service,
+		tracer:   trace.NewNoopTracerProvider().Tracer(""),
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+}
+
+func TestObserve(t *testing.T) {
+	listed := func(cl cc.Cluster, err error) func(context.Context, string) (cc.Cluster, error) {
+		return func(_ context.Context, name string) (cc.Cluster, error) {
+			if name != clusterName {
+				return cc.Cluster{}, errors.Errorf("want cluster name %q, got %q", clusterName, name)
+			}
+			return cl, err
+		}
+	}
+	described := func(d camunda.ClusterDetails, err error) func(context.Context, string) (camunda.ClusterDetails, error) {
+		return func(_ context.Context, id string) (camunda.ClusterDetails, error) {
+			if id != clusterID {
+				return camunda.ClusterDetails{}, errors.Errorf("want cluster ID %q, got %q", clusterID, id)
+			}
+			return d, err
+		}
 	}
 
+	healthy := details("Healthy", v1alpha1.ComponentStatusHealthy)
+	creating := details("Creating", v1alpha1.ComponentStatusCreating)
+	unhealthy := details("Not Healthy", "Unhealthy")
+	retryAt := time.Date(2021, time.March, 1, 12, 1, 0, 0, time.UTC)
+	throttled := &camunda.ThrottledError{StatusCode: 429, RetryAfter: time.Minute, RetryAt: retryAt}
+	now := metav1.Now()
+	protected := params
+	protected.DeletionProtection = true
+
 	type want struct {
 		o   managed.ExternalObservation
+		cr  resource.Managed
 		err error
 	}
 
 	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   want
+		reason  string
+		service camunda.ClusterClient
+		mg      resource.Managed
+		want    want
 	}{
-		// TODO: Add test cases.
+		"NotZeebeCluster": {
+			reason: "An error should be returned if the managed resource is not a ZeebeCluster.",
+			want:   want{err: errors.New(errNotMyType)},
+		},
+		"ObserveOnlyDeleted": {
+			reason: "A deleted observe-only ZeebeCluster should be released without calling the Console API.",
+			mg: func() resource.Managed {
+				cr := zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly))
+				cr.SetDeletionTimestamp(&now)
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+				cr: func() resource.Managed {
+					cr := zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly))
+					cr.SetDeletionTimestamp(&now)
+					return cr
+				}(),
+			},
+		},
+		"GetClusterError": {
+			reason: "Errors listing clusters should be returned.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName: listed(cc.Cluster{}, errBoom),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				cr:  zeebeCluster(withParameters(params)),
+				err: errBoom,
+			},
+		},
+		"Throttled": {
+			reason: "Throttled requests should be returned as errors, and reported in the Throttled condition.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName: listed(cc.Cluster{}, throttled),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				cr:  zeebeCluster(withParameters(params), withConditions(v1alpha1.Throttled())),
+				err: throttled,
+			},
+		},
+		"NotFound": {
+			reason: "A cluster that does not exist should be reported as such, so that it is created.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName: listed(cc.Cluster{}, nil),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(withParameters(params)),
+			},
+		},
+		"DeletionNoLongerBlocked": {
+			reason: "A blocked deletion should no longer be reported once deletion protection is disabled.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName: listed(cc.Cluster{}, nil),
+			},
+			mg: zeebeCluster(withParameters(params), withConditions(v1alpha1.DeletionBlocked())),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(withParameters(params), withConditions(v1alpha1.DeletionAllowed())),
+			},
+		},
+		"DeletionStillBlocked": {
+			reason: "A blocked deletion should still be reported while deletion protection is enabled.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName: listed(cc.Cluster{}, nil),
+			},
+			mg: zeebeCluster(withParameters(protected), withConditions(v1alpha1.DeletionBlocked())),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(withParameters(protected), withConditions(v1alpha1.DeletionBlocked())),
+			},
+		},
+		"NotFoundObserveOnly": {
+			reason: "An error should be returned if the cluster of an observe-only ZeebeCluster does not exist.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName: listed(cc.Cluster{}, nil),
+			},
+			mg: zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly)),
+			want: want{
+				cr:  zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly)),
+				err: errors.Errorf(errObserveOnlyNotFound, clusterName),
+			},
+		},
+		"DeletedOutsideProvider": {
+			reason: "A cluster that is neither listed nor found by its ID no longer exists.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cc.Cluster{}, nil),
+				MockGetClusterDetails: described(details(camunda.ClusterStatusNotFound, ""), nil),
+			},
+			mg: zeebeCluster(withParameters(params), withObservation(v1alpha1.ZeebeClusterObservation{ClusterId: clusterID})),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(withParameters(params), withObservation(v1alpha1.ZeebeClusterObservation{ClusterId: clusterID})),
+			},
+		},
+		"NotListedYet": {
+			reason: "A cluster that was just created should be found by its ID even if it is not listed yet.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cc.Cluster{}, nil),
+				MockGetClusterDetails: described(creating, nil),
+			},
+			mg: zeebeCluster(withParameters(params), withObservation(v1alpha1.ZeebeClusterObservation{ClusterId: clusterID})),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connectionDetails(observation(creating)),
+				},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(observation(creating)),
+					withConditions(append(components(v1alpha1.ComponentStatusCreating), xpv1.Creating())...),
+				),
+			},
+		},
+		"Healthy": {
+			reason: "A healthy cluster should be observed, and the ZeebeCluster should become available.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(healthy, nil),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connectionDetails(observation(healthy)),
+				},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(observation(healthy)),
+					withConditions(append(components(v1alpha1.ComponentStatusHealthy), xpv1.Available())...),
+				),
+			},
+		},
+		"Creating": {
+			reason: "A cluster that is being created should be observed, and the ZeebeCluster should be creating.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(creating, nil),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connectionDetails(observation(creating)),
+				},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(observation(creating)),
+					withConditions(append(components(v1alpha1.ComponentStatusCreating), xpv1.Creating())...),
+				),
+			},
+		},
+		"Unhealthy": {
+			reason: "An unhealthy cluster should be observed, and the ZeebeCluster should be unavailable.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(unhealthy, nil),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connectionDetails(observation(unhealthy)),
+				},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(observation(unhealthy)),
+					withConditions(append(components("Unhealthy"), xpv1.Unavailable())...),
+				),
+			},
+		},
+		"GetClusterDetailsError": {
+			reason: "A cluster whose details cannot be read should be unavailable, and considered out of date.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(camunda.ClusterDetails{}, errBoom),
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(v1alpha1.ZeebeClusterObservation{
+						ClusterId:      clusterID,
+						PlanName:       "Development",
+						ChannelName:    "Stable",
+						GenerationName: "Zeebe 1.0.0",
+					}),
+					withConditions(xpv1.Unavailable()),
+				),
+			},
+		},
+		"NeedsUpdate": {
+			reason: "A cluster on another channel than the desired one should be out of date.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(healthy, nil),
+			},
+			mg: zeebeCluster(withParameters(v1alpha1.ZeebeClusterParameters{ChannelName: "Alpha", PlanName: "Development", GenerationName: "Zeebe 1.0.0", Region: "Europe West 1D"})),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: connectionDetails(observation(healthy)),
+				},
+				cr: zeebeCluster(
+					withParameters(v1alpha1.ZeebeClusterParameters{ChannelName: "Alpha", PlanName: "Development", GenerationName: "Zeebe 1.0.0", Region: "Europe West 1D"}),
+					withObservation(func() v1alpha1.ZeebeClusterObservation {
+						o := observation(healthy)
+						o.PendingChanges = []string{`channel: "Stable" -> "Alpha"`}
+						return o
+					}()),
+					withConditions(append(components(v1alpha1.ComponentStatusHealthy), xpv1.Available())...),
+				),
+			},
+		},
+		"ChangesNoLongerDue": {
+			reason: "The changes due should no longer be reported once the cluster matches its parameters.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(healthy, nil),
+			},
+			mg: zeebeCluster(
+				withParameters(params),
+				withConditions(v1alpha1.ChangesDue([]string{`channel: "Stable" -> "Alpha"`})),
+			),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: connectionDetails(observation(healthy)),
+				},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(observation(healthy)),
+					withConditions(append(components(v1alpha1.ComponentStatusHealthy), xpv1.Available(), v1alpha1.NoChangesDue())...),
+				),
+			},
+		},
+		"LateInitialize": {
+			reason: "Parameters that are not set should be filled in from the observed cluster.",
+			service: &fake.MockClusterClient{
+				MockGetClusterByName:  listed(cluster, nil),
+				MockGetClusterDetails: described(healthy, nil),
+			},
+			mg: zeebeCluster(),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+					ConnectionDetails:       connectionDetails(observation(healthy)),
+				},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(observation(healthy)),
+					withConditions(append(components(v1alpha1.ComponentStatusHealthy), xpv1.Available())...),
+				),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: cc.CCClient{}}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			e := newExternal(tc.service)
+			got, err := e.Observe(context.Background(), tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.cr, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		c   managed.ExternalCreation
+		cr  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service camunda.ClusterClient
+		mg      resource.Managed
+		want    want
+	}{
+		"NotZeebeCluster": {
+			reason: "An error should be returned if the managed resource is not a ZeebeCluster.",
+			want:   want{err: errors.New(errNotMyType)},
+		},
+		"ObserveOnly": {
+			reason: "A cluster should never be created for an observe-only ZeebeCluster.",
+			mg:     zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly)),
+			want: want{
+				cr:  zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly)),
+				err: errors.New(errObserveOnly),
+			},
+		},
+		"CreateClusterError": {
+			reason: "Errors creating a cluster should be returned.",
+			service: &fake.MockClusterClient{
+				MockCreateClusterParams: func(_ context.Context, _, _, _, _, _ string) (string, error) {
+					return "", errBoom
+				},
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				cr:  zeebeCluster(withParameters(params)),
+				err: errBoom,
+			},
+		},
+		"Created": {
+			reason: "A cluster should be created from the ZeebeCluster's parameters, and its ID observed.",
+			service: &fake.MockClusterClient{
+				MockCreateClusterParams: func(_ context.Context, name, plan, channel, generation, region string) (string, error) {
+					got := []string{name, plan, channel, generation, region}
+					want := []string{clusterName, params.PlanName, params.ChannelName, params.GenerationName, params.Region}
+					if diff := cmp.Diff(want, got); diff != "" {
+						return "", errors.Errorf("CreateClusterWithParamsAndContext(...): -want, +got:\n%s", diff)
+					}
+					return clusterID, nil
+				},
+			},
+			mg: zeebeCluster(withParameters(params)),
+			want: want{
+				c: managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(
+					withParameters(params),
+					withObservation(v1alpha1.ZeebeClusterObservation{ClusterId: clusterID}),
+					withConditions(xpv1.Creating()),
+				),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(tc.service)
+			got, err := e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	upgraded := v1alpha1.ZeebeClusterParameters{
+		PlanName:       "Development",
+		ChannelName:    "Alpha",
+		GenerationName: "Zeebe 1.1.0",
+		Region:         "Europe West 1D",
+	}
+	upgradedGeneration := params
+	upgradedGeneration.GenerationName = "Zeebe 1.1.0"
+
+	// A window that opens for a minute in two hours, so it is closed while
+	// the test runs.
+	closed := &v1alpha1.MaintenanceWindow{
+		Start:    time.Now().UTC().Add(2 * time.Hour).Format("15:04"),
+		Duration: metav1.Duration{Duration: time.Minute},
+	}
+	inWindow := func(p v1alpha1.ZeebeClusterParameters, w *v1alpha1.MaintenanceWindow) v1alpha1.ZeebeClusterParameters {
+		p.MaintenanceWindow = w
+		return p
+	}
+	allDue := v1alpha1.ChangesDue([]string{`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`, `channel: "Stable" -> "Alpha"`})
+	channelDue := v1alpha1.ChangesDue([]string{`channel: "Stable" -> "Alpha"`})
+	pending := func(changes ...string) v1alpha1.ZeebeClusterObservation {
+		o := observation(details("Healthy", v1alpha1.ComponentStatusHealthy))
+		o.PendingChanges = changes
+		return o
+	}
+
+	type want struct {
+		u      managed.ExternalUpdate
+		cr     resource.Managed
+		events []recordedEvent
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service camunda.ClusterClient
+		mg      resource.Managed
+		want    want
+	}{
+		"NotZeebeCluster": {
+			reason: "An error should be returned if the managed resource is not a ZeebeCluster.",
+			want:   want{err: errors.New(errNotMyType)},
+		},
+		"ObserveOnly": {
+			reason: "An observe-only cluster should never be updated.",
+			mg: zeebeCluster(
+				withManagementPolicy(v1alpha1.ManagementObserveOnly),
+				withParameters(upgraded),
+				withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy))),
+			),
+			want: want{
+				cr: zeebeCluster(
+					withManagementPolicy(v1alpha1.ManagementObserveOnly),
+					withParameters(upgraded),
+					withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy))),
+				),
+			},
+		},
+		"UpToDate": {
+			reason: "A cluster that matches its parameters should not be updated.",
+			// Calling any of the fake's functions would panic.
+			service: &fake.MockClusterClient{},
+			mg:      zeebeCluster(withParameters(params), withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy)))),
+			want: want{
+				cr: zeebeCluster(withParameters(params), withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy)))),
+			},
+		},
+		"ChangesDue": {
+			reason: "Changes that are due should be reported as pending, in a condition and in an event, since the Console API cannot make them.",
+			// Calling any of the fake's functions would panic.
+			service: &fake.MockClusterClient{},
+			mg:      zeebeCluster(withParameters(upgraded), withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy)))),
+			want: want{
+				u: managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(
+					withParameters(upgraded),
+					withObservation(pending(`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`, `channel: "Stable" -> "Alpha"`)),
+					withConditions(allDue),
+				),
+				events: []recordedEvent{{
+					Type:    event.TypeWarning,
+					Reason:  reasonChangesDue,
+					Message: allDue.Message,
+				}},
+			},
+		},
+		"ChangesAlreadyReported": {
+			reason:  "Changes that are still due should not be reported in another event.",
+			service: &fake.MockClusterClient{},
+			mg: zeebeCluster(
+				withParameters(upgraded),
+				withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy))),
+				withConditions(allDue),
+			),
+			want: want{
+				u: managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(
+					withParameters(upgraded),
+					withObservation(pending(`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`, `channel: "Stable" -> "Alpha"`)),
+					withConditions(allDue),
+				),
+			},
+		},
+		"OutsideMaintenanceWindow": {
+			reason:  "Disruptive changes should not be due until the maintenance window opens, while other changes are due right away.",
+			service: &fake.MockClusterClient{},
+			mg: zeebeCluster(
+				withParameters(inWindow(upgraded, closed)),
+				withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy))),
+			),
+			want: want{
+				u: managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}},
+				cr: zeebeCluster(
+					withParameters(inWindow(upgraded, closed)),
+					withObservation(pending(`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`, `channel: "Stable" -> "Alpha"`)),
+					withConditions(channelDue),
+				),
+				events: []recordedEvent{{
+					Type:    event.TypeWarning,
+					Reason:  reasonChangesDue,
+					Message: channelDue.Message,
+				}},
+			},
+		},
+		"OnlyDeferredChanges": {
+			reason:  "Nothing should be due while only disruptive changes wait for the maintenance window.",
+			service: &fake.MockClusterClient{},
+			mg: zeebeCluster(
+				withParameters(inWindow(upgradedGeneration, closed)),
+				withObservation(observation(details("Healthy", v1alpha1.ComponentStatusHealthy))),
+			),
+			want: want{
+				cr: zeebeCluster(
+					withParameters(inWindow(upgradedGeneration, closed)),
+					withObservation(pending(`generation: "Zeebe 1.0.0" -> "Zeebe 1.1.0"`)),
+				),
+			},
+		},
+		"UpgradedByCamunda": {
+			reason:  "A cluster that Camunda upgraded past the generation it was created with should not be downgraded.",
+			service: &fake.MockClusterClient{},
+			mg: zeebeCluster(withParameters(params), withObservation(func() v1alpha1.ZeebeClusterObservation {
+				o := observation(details("Healthy", v1alpha1.ComponentStatusHealthy))
+				o.GenerationName = "Zeebe 1.1.0"
+				return o
+			}())),
+			want: want{
+				cr: zeebeCluster(withParameters(params), withObservation(func() v1alpha1.ZeebeClusterObservation {
+					o := observation(details("Healthy", v1alpha1.ComponentStatusHealthy))
+					o.GenerationName = "Zeebe 1.1.0"
+					return o
+				}())),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(tc.service)
+			r := &fakeRecorder{}
+			e.recorder = r
+			got, err := e.Update(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.u, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			// When the next maintenance window opens depends on when the test
+			// runs.
+			ignoreNext := cmpopts.IgnoreFields(v1alpha1.ZeebeClusterObservation{}, "NextMaintenanceWindow")
+			if diff := cmp.Diff(tc.want.cr, tc.mg, test.EquateConditions(), ignoreNext); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	deleted := func(ok bool, err error) func(context.Context, string) (bool, error) {
+		return func(_ context.Context, id string) (bool, error) {
+			if id != clusterID {
+				return false, errors.Errorf("want cluster ID %q, got %q", clusterID, id)
+			}
+			return ok, err
+		}
+	}
+	retryAt := time.Date(2021, time.March, 1, 12, 1, 0, 0, time.UTC)
+	throttled := &camunda.ThrottledError{StatusCode: 429, RetryAfter: time.Minute, RetryAt: retryAt}
+	observed := withObservation(v1alpha1.ZeebeClusterObservation{ClusterId: clusterID})
+
+	type want struct {
+		cr  resource.Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		service camunda.ClusterClient
+		mg      resource.Managed
+		want    want
+	}{
+		"NotZeebeCluster": {
+			reason: "An error should be returned if the managed resource is not a ZeebeCluster.",
+			want:   want{err: errors.New(errNotMyType)},
+		},
+		"ObserveOnly": {
+			reason: "An observe-only cluster should never be deleted.",
+			mg:     zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly), observed),
+			want: want{
+				cr: zeebeCluster(withManagementPolicy(v1alpha1.ManagementObserveOnly), observed),
+			},
+		},
+		"DeletionProtection": {
+			reason: "A cluster with deletion protection enabled should not be deleted, and the resource should say why.",
+			mg:     zeebeCluster(withParameters(v1alpha1.ZeebeClusterParameters{DeletionProtection: true}), observed),
+			want: want{
+				cr: zeebeCluster(
+					withParameters(v1alpha1.ZeebeClusterParameters{DeletionProtection: true}),
+					observed,
+					withConditions(v1alpha1.DeletionBlocked()),
+				),
+				err: errors.New(errDeletionProtected),
+			},
+		},
+		"Deleted": {
+			reason: "The observed cluster should be deleted.",
+			service: &fake.MockClusterClient{
+				MockDeleteCluster: deleted(true, nil),
+			},
+			mg:   zeebeCluster(withParameters(params), observed),
+			want: want{cr: zeebeCluster(withParameters(params), observed)},
+		},
+		"DeleteClusterError": {
+			reason: "A cluster that cannot be deleted is assumed to no longer exist.",
+			service: &fake.MockClusterClient{
+				MockDeleteCluster: deleted(false, errBoom),
+			},
+			mg:   zeebeCluster(withParameters(params), observed),
+			want: want{cr: zeebeCluster(withParameters(params), observed)},
+		},
+		"Throttled": {
+			reason: "A throttled deletion should be returned as an error, and reported in the Throttled condition.",
+			service: &fake.MockClusterClient{
+				MockDeleteCluster: deleted(false, throttled),
+			},
+			mg: zeebeCluster(withParameters(params), observed),
+			want: want{
+				cr:  zeebeCluster(withParameters(params), observed, withConditions(v1alpha1.Throttled())),
+				err: throttled,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(tc.service)
+			err := e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObservedStatus(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      camunda.ClusterStatus
+		want   v1alpha1.ClusterStatus
+	}{
+		"AllFields": {
+			reason: "The health and addresses of the cluster and its components should be observed.",
+			s: camunda.ClusterStatus{
+				ClusterStatus: cc.ClusterStatus{
+					Ready:          "Healthy",
+					ZeebeStatus:    "Healthy",
+					ZeebeURL:       "cool.zeebe.camunda.io:443",
+					OperateStatus:  "Unhealthy",
+					OperateURL:     "https://operate",
+					TaskListStatus: "Creating",
+					TaskListURL:    "https://tasklist",
+				},
+				OptimizeStatus: "Healthy",
+				OptimizeURL:    "https://optimize",
+			},
+			want: v1alpha1.ClusterStatus{
+				Ready:          "Healthy",
+				ZeebeStatus:    "Healthy",
+				ZeebeURL:       "cool.zeebe.camunda.io:443",
+				OperateStatus:  "Unhealthy",
+				OperateURL:     "https://operate",
+				TaskListStatus: "Creating",
+				TaskListURL:    "https://tasklist",
+				OptimizeStatus: "Healthy",
+				OptimizeURL:    "https://optimize",
+			},
+		},
+		"NotFound": {
+			reason: "A cluster that was not found should be observed as such.",
+			s:      camunda.ClusterStatus{ClusterStatus: cc.ClusterStatus{Ready: camunda.ClusterStatusNotFound}},
+			want:   v1alpha1.ClusterStatus{Ready: camunda.ClusterStatusNotFound},
+		},
+		"Empty": {
+			reason: "An empty status should be observed as empty.",
+			want:   v1alpha1.ClusterStatus{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := observedStatus(tc.s)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nobservedStatus(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetObservation(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+	cases := map[string]struct {
+		reason  string
+		o       v1alpha1.ZeebeClusterObservation
+		details camunda.ClusterDetails
+		want    v1alpha1.ZeebeClusterObservation
+	}{
+		"AllDetails": {
+			reason: "IDs, display names, addresses, timestamps and the owner should be observed.",
+			o:      v1alpha1.ZeebeClusterObservation{ClusterId: "cool", OrganizationId: "org"},
+			details: camunda.ClusterDetails{
+				Cluster: cc.Cluster{
+					Created:          "2021-03-01T12:00:00Z",
+					ClusterPlantType: cc.ClusterPlantType{Id: "p1", Name: "Development"},
+					Channel:          cc.Channel{Id: "c1", Name: "Stable"},
+					Generation:       cc.Generation{Id: "g1", Name: "Zeebe 1.0.0"},
+					K8sContext:       cc.K8sContext{ID: "r1", Name: "Europe West 1D"},
+				},
+				Links: camunda.ClusterLinks{Zeebe: "cool.zeebe.camunda.io:443", Operate: "https://operate"},
+				Status: camunda.ClusterStatus{
+					ClusterStatus: cc.ClusterStatus{OperateURL: "https://ignored", TaskListURL: "https://tasklist"},
+					OptimizeURL:   "https://optimize",
+				},
+				Owner: camunda.ClusterOwner{ID: "u1", Name: "Jane Doe"},
+			},
+			want: v1alpha1.ZeebeClusterObservation{
+				ClusterId:      "cool",
+				OrganizationId: "org",
+				PlanId:         "p1",
+				PlanName:       "Development",
+				ChannelId:      "c1",
+				ChannelName:    "Stable",
+				GenerationId:   "g1",
+				GenerationName: "Zeebe 1.0.0",
+				RegionId:       "r1",
+				RegionName:     "Europe West 1D",
+				ZeebeAddress:   "cool.zeebe.camunda.io:443",
+				OperateURL:     "https://operate",
+				TasklistURL:    "https://tasklist",
+				OptimizeURL:    "https://optimize",
+				Created:        &created,
+				Owner:          "Jane Doe",
+			},
+		},
+		"MalformedTimestamps": {
+			reason: "Timestamps that cannot be parsed should not be observed, and the owner's ID used if it has no name.",
+			details: camunda.ClusterDetails{
+				Cluster:     cc.Cluster{Created: "yesterday"},
+				LastUpgrade: "never",
+				Owner:       camunda.ClusterOwner{ID: "u1"},
+			},
+			want: v1alpha1.ZeebeClusterObservation{Owner: "u1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setObservation(&tc.o, tc.details)
+			if diff := cmp.Diff(tc.want, tc.o); diff != "" {
+				t.Errorf("\n%s\nsetObservation(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      v1alpha1.ZeebeClusterObservation
+		want   managed.ConnectionDetails
+	}{
+		"NotObserved": {
+			reason: "No connection details should be returned for a cluster that was not observed yet.",
+			want:   managed.ConnectionDetails{},
+		},
+		"Observed": {
+			reason: "The cluster ID, gateway address and observed URLs should be returned.",
+			o: v1alpha1.ZeebeClusterObservation{
+				ClusterId:    "cool",
+				ZeebeAddress: "cool.zeebe.camunda.io:443",
+				OperateURL:   "https://operate",
+			},
+			want: managed.ConnectionDetails{
+				keyClusterID:    []byte("cool"),
+				keyZeebeAddress: []byte("cool.zeebe.camunda.io:443"),
+				keyOperateURL:   []byte("https://operate"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := connectionDetails(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nconnectionDetails(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	srv := httptest.NewServer(console.New())
+	defer srv.Close()
+	kube := newConnector(srv).kube
+
+	type want struct {
+		org string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"GetProviderConfigError": {
+			reason: "Errors getting the ProviderConfig should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetPC)},
+		},
+		"WrongOrganization": {
+			reason: "Credentials of an organization other than the ProviderConfig's should be refused.",
+			kube: &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				if err := kube.Get(ctx, key, obj); err != nil {
+					return err
+				}
+				if pc, ok := obj.(*apisv1alpha1.ProviderConfig); ok {
+					pc.Spec.OrganizationID = "other"
+				}
+				return nil
+			}},
+			want: want{err: errors.Wrap(errors.Errorf("credentials belong to organization %q, not %q", console.DefaultOrganizationID, "other"), errValidateOrganization)},
+		},
+		"LoggedIn": {
+			reason: "The organization of the ProviderConfig's credentials should be returned.",
+			kube:   kube,
+			want:   want{org: console.DefaultOrganizationID},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, org, err := Login(context.Background(), tc.kube, "default", logging.NewNopLogger())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLogin(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.org, org); diff != "" {
+				t.Errorf("\n%s\nLogin(...): -want organization, +got organization:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
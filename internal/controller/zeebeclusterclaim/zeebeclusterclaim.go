@@ -0,0 +1,387 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zeebeclusterclaim binds namespaced ZeebeClusterClaims to the
+// ZeebeClusters they request.
+package zeebeclusterclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	apisv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/options"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+
+	// A claim whose namespace may not use its ProviderConfig is checked
+	// again after this long, since granting access does not change the
+	// claim.
+	forbiddenWait = 1 * time.Minute
+
+	finalizer = "finalizer.zeebeclusterclaim.cc.camunda.crossplane.io"
+)
+
+// Labels of a ZeebeCluster that record the claim it is bound to.
+const (
+	LabelKeyClaimNamespace = "cc.camunda.crossplane.io/claim-namespace"
+	LabelKeyClaimName      = "cc.camunda.crossplane.io/claim-name"
+)
+
+// VerbUse is the verb an RBAC role must grant on a ProviderConfig for the
+// service accounts of a namespace for ZeebeClusterClaims in that namespace to
+// use it.
+const VerbUse = "use"
+
+const (
+	errGetClaim        = "cannot get ZeebeClusterClaim"
+	errGetCluster      = "cannot get claimed ZeebeCluster"
+	errApplyCluster    = "cannot apply claimed ZeebeCluster"
+	errDeleteCluster   = "cannot delete claimed ZeebeCluster"
+	errAddFinalizer    = "cannot add ZeebeClusterClaim finalizer"
+	errRemoveFinalizer = "cannot remove ZeebeClusterClaim finalizer"
+	errUpdateStatus    = "cannot update ZeebeClusterClaim status"
+	errReviewAccess    = "cannot review access to ProviderConfig"
+	errForbidden       = "namespace %q may not use ProviderConfig %q"
+	errBoundElsewhere  = "ZeebeCluster %q is bound to another claim"
+)
+
+// Event reasons.
+const (
+	reasonBind   event.Reason = "BindZeebeCluster"
+	reasonDenied event.Reason = "ProviderConfigDenied"
+)
+
+// Setup adds a controller that binds ZeebeClusterClaims to ZeebeClusters.
+func Setup(mgr ctrl.Manager, l logging.Logger, o options.Options) error {
+	name := "claim/" + strings.ToLower(v1alpha1.ZeebeClusterClaimGroupKind)
+
+	r := NewReconciler(mgr.GetClient(),
+		WithLogger(l.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ZeebeClusterClaim{}).
+		Watches(&source.Kind{Type: &v1alpha1.ZeebeCluster{}}, handler.EnqueueRequestsFromMapFunc(requestForClaim)).
+		Complete(r)
+}
+
+// requestForClaim maps a ZeebeCluster to a request for the claim it is bound
+// to, if any.
+func requestForClaim(o client.Object) []reconcile.Request {
+	ns, name := o.GetLabels()[LabelKeyClaimNamespace], o.GetLabels()[LabelKeyClaimName]
+	if ns == "" || name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: ns, Name: name}}}
+}
+
+// An Authorizer tells whether the ZeebeClusterClaims of a namespace may use a
+// ProviderConfig, and why not if they may not.
+type Authorizer interface {
+	MayUse(ctx context.Context, namespace, providerConfig string) (bool, string, error)
+}
+
+// An AuthorizerFn is a function that satisfies Authorizer.
+type AuthorizerFn func(ctx context.Context, namespace, providerConfig string) (bool, string, error)
+
+// MayUse tells whether the ZeebeClusterClaims of a namespace may use a
+// ProviderConfig.
+func (fn AuthorizerFn) MayUse(ctx context.Context, namespace, providerConfig string) (bool, string, error) {
+	return fn(ctx, namespace, providerConfig)
+}
+
+// A SubjectAccessReviewer authorizes the use of a ProviderConfig by asking
+// the API server whether the service accounts of a namespace may use it.
+// RBAC grants that with a RoleBinding in the namespace to a role with the use
+// verb on the ProviderConfig, whose subject is the group of the namespace's
+// service accounts.
+type SubjectAccessReviewer struct {
+	client client.Client
+}
+
+// NewSubjectAccessReviewer returns an Authorizer that creates
+// SubjectAccessReviews with the supplied client.
+func NewSubjectAccessReviewer(c client.Client) *SubjectAccessReviewer {
+	return &SubjectAccessReviewer{client: c}
+}
+
+// MayUse tells whether the ZeebeClusterClaims of a namespace may use a
+// ProviderConfig.
+func (r *SubjectAccessReviewer) MayUse(ctx context.Context, namespace, providerConfig string) (bool, string, error) {
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			Groups: []string{serviceAccountsGroup(namespace)},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      VerbUse,
+				Group:     apisv1alpha1.Group,
+				Resource:  "providerconfigs",
+				Name:      providerConfig,
+			},
+		},
+	}
+	if err := r.client.Create(ctx, sar); err != nil {
+		return false, "", errors.Wrap(err, errReviewAccess)
+	}
+	return sar.Status.Allowed, sar.Status.Reason, nil
+}
+
+// serviceAccountsGroup returns the group the service accounts of the supplied
+// namespace belong to.
+func serviceAccountsGroup(namespace string) string {
+	return "system:serviceaccounts:" + namespace
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithAuthorizer specifies how the Reconciler should tell whether a claim
+// may use its ProviderConfig.
+func WithAuthorizer(a Authorizer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.authorizer = a
+	}
+}
+
+// WithFinalizer specifies how the Reconciler should add and remove its
+// finalizer to and from claims.
+func WithFinalizer(f resource.Finalizer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.finalizer = f
+	}
+}
+
+// A Reconciler binds ZeebeClusterClaims to ZeebeClusters. Each claim is bound
+// to a ZeebeCluster named after the claim's namespace and name, which writes
+// its connection secret to the claim's namespace.
+type Reconciler struct {
+	client     client.Client
+	authorizer Authorizer
+	finalizer  resource.Finalizer
+	log        logging.Logger
+	record     event.Recorder
+}
+
+// NewReconciler returns a Reconciler of ZeebeClusterClaims.
+func NewReconciler(c client.Client, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:     c,
+		authorizer: NewSubjectAccessReviewer(c),
+		finalizer:  resource.NewAPIFinalizer(c, finalizer),
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile a ZeebeClusterClaim by binding it to a ZeebeCluster.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { // nolint:gocyclo
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	cm := &v1alpha1.ZeebeClusterClaim{}
+	if err := r.client.Get(ctx, req.NamespacedName, cm); err != nil {
+		log.Debug(errGetClaim, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetClaim)
+	}
+
+	zb := &v1alpha1.ZeebeCluster{}
+	nn := types.NamespacedName{Name: ClusterName(cm)}
+	err := r.client.Get(ctx, nn, zb)
+	if resource.IgnoreNotFound(err) != nil {
+		cm.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errGetCluster)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+	}
+	exists := err == nil
+
+	if meta.WasDeleted(cm) {
+		// A ZeebeCluster that is bound to another claim is not this claim's
+		// to delete.
+		if exists && boundTo(zb, cm) && !meta.WasDeleted(zb) {
+			if err := r.client.Delete(ctx, zb); resource.IgnoreNotFound(err) != nil {
+				cm.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errDeleteCluster)))
+				return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+			}
+			log.Debug("Deleted claimed ZeebeCluster", "cluster", nn.Name)
+		}
+		if err := r.finalizer.RemoveFinalizer(ctx, cm); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errRemoveFinalizer)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if exists && !boundTo(zb, cm) {
+		cm.SetConditions(xpv1.ReconcileError(errors.Errorf(errBoundElsewhere, nn.Name)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+	}
+
+	if err := r.finalizer.AddFinalizer(ctx, cm); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+	}
+
+	pc := providerConfigName(cm)
+	ok, reason, err := r.authorizer.MayUse(ctx, cm.GetNamespace(), pc)
+	if err != nil {
+		cm.SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+	}
+	if !ok {
+		err := errors.Errorf(errForbidden, cm.GetNamespace(), pc)
+		if reason != "" {
+			err = errors.Wrap(err, reason)
+		}
+		log.Debug("Claim may not use its ProviderConfig", "provider-config", pc)
+		r.record.Event(cm, event.Warning(reasonDenied, err))
+		cm.SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{RequeueAfter: forbiddenWait}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+	}
+
+	want := zb.DeepCopy()
+	want.SetName(nn.Name)
+	configure(want, cm)
+	switch {
+	case !exists:
+		err = r.client.Create(ctx, want)
+	case !equality.Semantic.DeepEqual(zb, want):
+		err = r.client.Update(ctx, want)
+	}
+	if err != nil {
+		cm.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errApplyCluster)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+	}
+	if !exists {
+		log.Debug("Created claimed ZeebeCluster", "cluster", nn.Name)
+		r.record.Event(cm, event.Normal(reasonBind, fmt.Sprintf("Bound to ZeebeCluster %q", nn.Name)))
+	}
+
+	cm.Status.ClusterReference = &xpv1.Reference{Name: nn.Name}
+	cm.Status.AtProvider = *want.Status.AtProvider.DeepCopy()
+	cm.SetConditions(xpv1.ReconcileSuccess())
+	if c := want.GetCondition(xpv1.TypeReady); c.Reason != "" {
+		cm.SetConditions(c)
+	} else {
+		cm.SetConditions(xpv1.Creating())
+	}
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateStatus)
+}
+
+// ClusterName returns the name of the ZeebeCluster the supplied claim is bound
+// to. Distinct claims whose names are the same when joined with their
+// namespaces are told apart by the labels of their ZeebeClusters.
+func ClusterName(cm *v1alpha1.ZeebeClusterClaim) string {
+	return cm.GetNamespace() + "-" + cm.GetName()
+}
+
+// boundTo tells whether the supplied ZeebeCluster is bound to the supplied
+// claim.
+func boundTo(zb *v1alpha1.ZeebeCluster, cm *v1alpha1.ZeebeClusterClaim) bool {
+	l := zb.GetLabels()
+	return l[LabelKeyClaimNamespace] == cm.GetNamespace() && l[LabelKeyClaimName] == cm.GetName()
+}
+
+func providerConfigName(cm *v1alpha1.ZeebeClusterClaim) string {
+	if ref := cm.Spec.ProviderConfigReference; ref != nil && ref.Name != "" {
+		return ref.Name
+	}
+	return "default"
+}
+
+// configure the supplied ZeebeCluster as requested by the supplied claim.
+// Parameters that the claim does not set are left alone, so that those the
+// ZeebeCluster was late initialized with are kept.
+func configure(zb *v1alpha1.ZeebeCluster, cm *v1alpha1.ZeebeClusterClaim) {
+	meta.AddLabels(zb, map[string]string{
+		LabelKeyClaimNamespace: cm.GetNamespace(),
+		LabelKeyClaimName:      cm.GetName(),
+	})
+
+	zb.Spec.ProviderConfigReference = &xpv1.Reference{Name: providerConfigName(cm)}
+	zb.Spec.WriteConnectionSecretToReference = nil
+	if ref := cm.Spec.WriteConnectionSecretToReference; ref != nil {
+		zb.Spec.WriteConnectionSecretToReference = &xpv1.SecretReference{Namespace: cm.GetNamespace(), Name: ref.Name}
+	}
+	zb.Spec.DeletionPolicy = cm.Spec.DeletionPolicy
+	if zb.Spec.DeletionPolicy == "" {
+		zb.Spec.DeletionPolicy = xpv1.DeletionDelete
+	}
+	// Claims can only request clusters of their own.
+	zb.Spec.ManagementPolicy = v1alpha1.ManagementFullControl
+
+	p, dp := cm.Spec.ForProvider, &zb.Spec.ForProvider
+	for _, f := range []struct {
+		from string
+		to   *string
+	}{
+		{from: p.PlanName, to: &dp.PlanName},
+		{from: p.PlanId, to: &dp.PlanId},
+		{from: p.ChannelName, to: &dp.ChannelName},
+		{from: p.ChannelId, to: &dp.ChannelId},
+		{from: p.GenerationName, to: &dp.GenerationName},
+		{from: p.GenerationId, to: &dp.GenerationId},
+		{from: p.Region, to: &dp.Region},
+		{from: p.RegionId, to: &dp.RegionId},
+	} {
+		if f.from != "" {
+			*f.to = f.from
+		}
+	}
+	dp.DeletionProtection = p.DeletionProtection
+	dp.MaintenanceWindow = p.MaintenanceWindow.DeepCopy()
+}
@@ -0,0 +1,497 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zeebeclusterclaim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+)
+
+const (
+	namespace = "team-a"
+	claimName = "cool"
+)
+
+var (
+	errBoom = errors.New("boom")
+	deleted = metav1.Now()
+)
+
+type claimModifier func(cm *v1alpha1.ZeebeClusterClaim)
+
+func withConditions(c ...xpv1.Condition) claimModifier {
+	return func(cm *v1alpha1.ZeebeClusterClaim) { cm.SetConditions(c...) }
+}
+
+func withClusterRef() claimModifier {
+	return func(cm *v1alpha1.ZeebeClusterClaim) {
+		cm.Status.ClusterReference = &xpv1.Reference{Name: namespace + "-" + claimName}
+	}
+}
+
+func withAtProvider(o v1alpha1.ZeebeClusterObservation) claimModifier {
+	return func(cm *v1alpha1.ZeebeClusterClaim) { cm.Status.AtProvider = o }
+}
+
+func withDeletionTimestamp() claimModifier {
+	return func(cm *v1alpha1.ZeebeClusterClaim) {
+		cm.SetDeletionTimestamp(&deleted)
+	}
+}
+
+func claim(m ...claimModifier) *v1alpha1.ZeebeClusterClaim {
+	cm := &v1alpha1.ZeebeClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: claimName},
+		Spec: v1alpha1.ZeebeClusterClaimSpec{
+			ProviderConfigReference:          &xpv1.Reference{Name: "team-a"},
+			WriteConnectionSecretToReference: &xpv1.LocalSecretReference{Name: "cool-zeebe"},
+			ForProvider:                      v1alpha1.ZeebeClusterParameters{PlanName: "Development", Region: "Europe West 1D"},
+		},
+	}
+	for _, f := range m {
+		f(cm)
+	}
+	return cm
+}
+
+type clusterModifier func(zb *v1alpha1.ZeebeCluster)
+
+func withLabels(ns, name string) clusterModifier {
+	return func(zb *v1alpha1.ZeebeCluster) {
+		zb.SetLabels(map[string]string{LabelKeyClaimNamespace: ns, LabelKeyClaimName: name})
+	}
+}
+
+func withForProvider(p v1alpha1.ZeebeClusterParameters) clusterModifier {
+	return func(zb *v1alpha1.ZeebeCluster) { zb.Spec.ForProvider = p }
+}
+
+func withStatus(o v1alpha1.ZeebeClusterObservation, c ...xpv1.Condition) clusterModifier {
+	return func(zb *v1alpha1.ZeebeCluster) {
+		zb.Status.AtProvider = o
+		zb.SetConditions(c...)
+	}
+}
+
+// cluster returns the ZeebeCluster that claim() is bound to.
+func cluster(m ...clusterModifier) *v1alpha1.ZeebeCluster {
+	zb := &v1alpha1.ZeebeCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace + "-" + claimName},
+		Spec: v1alpha1.ZeebeClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference:          &xpv1.Reference{Name: "team-a"},
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: namespace, Name: "cool-zeebe"},
+				DeletionPolicy:                   xpv1.DeletionDelete,
+			},
+			ManagementPolicy: v1alpha1.ManagementFullControl,
+			ForProvider:      v1alpha1.ZeebeClusterParameters{PlanName: "Development", Region: "Europe West 1D"},
+		},
+	}
+	withLabels(namespace, claimName)(zb)
+	for _, f := range m {
+		f(zb)
+	}
+	return zb
+}
+
+// get returns a MockGetFn that gets the supplied claim, and the supplied
+// ZeebeCluster or none if it is nil.
+func get(cm *v1alpha1.ZeebeClusterClaim, zb *v1alpha1.ZeebeCluster) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha1.ZeebeClusterClaim:
+			cm.DeepCopyInto(o)
+		case *v1alpha1.ZeebeCluster:
+			if zb == nil {
+				return kerrors.NewNotFound(schema.GroupResource{}, "")
+			}
+			zb.DeepCopyInto(o)
+		}
+		return nil
+	}
+}
+
+// wantStatus returns a MockStatusUpdateFn that fails the test unless the
+// claim's status is updated to that of the supplied claim.
+func wantStatus(t *testing.T, want *v1alpha1.ZeebeClusterClaim) test.MockStatusUpdateFn {
+	return func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+		t.Helper()
+		if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+			t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+		}
+		return nil
+	}
+}
+
+// wantCluster returns a function that fails the test unless it is called with
+// the supplied ZeebeCluster.
+func wantCluster(t *testing.T, op string, want *v1alpha1.ZeebeCluster) func(_ context.Context, obj client.Object) error {
+	return func(_ context.Context, obj client.Object) error {
+		t.Helper()
+		if diff := cmp.Diff(want, obj); diff != "" {
+			t.Errorf("%s(...): -want, +got:\n%s", op, diff)
+		}
+		return nil
+	}
+}
+
+func unexpected(op string) func(context.Context, client.Object) error {
+	return func(context.Context, client.Object) error {
+		return errors.Errorf("unexpected call to %s", op)
+	}
+}
+
+func allow(context.Context, string, string) (bool, string, error) { return true, "", nil }
+
+func TestReconcile(t *testing.T) {
+	observed := v1alpha1.ZeebeClusterObservation{ClusterId: "cool-id", PlanName: "Development"}
+	finalizer := resource.FinalizerFns{
+		AddFinalizerFn:    func(context.Context, resource.Object) error { return nil },
+		RemoveFinalizerFn: func(context.Context, resource.Object) error { return nil },
+	}
+
+	type args struct {
+		kube       func(t *testing.T) client.Client
+		authorizer Authorizer
+		finalizer  resource.Finalizer
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ClaimNotFound": {
+			reason: "A claim that no longer exists should be ignored.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, claimName))}
+				},
+			},
+		},
+		"GetClaimError": {
+			reason: "An error getting the claim should be returned.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetClaim)},
+		},
+		"BoundElsewhere": {
+			reason: "A claim whose ZeebeCluster is bound to another claim should not take it over.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet: get(claim(), cluster(withLabels("team", "a-cool"))),
+						MockUpdate: func(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							return unexpected("Update")(ctx, obj)
+						},
+						MockStatusUpdate: wantStatus(t, claim(withConditions(xpv1.ReconcileError(errors.Errorf(errBoundElsewhere, namespace+"-"+claimName))))),
+					}
+				},
+			},
+		},
+		"DeletedWhileBoundElsewhere": {
+			reason: "A deleted claim whose ZeebeCluster is bound to another claim should release its finalizer without touching the ZeebeCluster.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet: get(claim(withDeletionTimestamp()), cluster(withLabels("team", "a-cool"))),
+						MockDelete: func(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+							return unexpected("Delete")(ctx, obj)
+						},
+						MockStatusUpdate: func(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							return unexpected("Status().Update")(ctx, obj)
+						},
+					}
+				},
+			},
+		},
+		"Forbidden": {
+			reason: "A claim whose namespace may not use its ProviderConfig should not be bound, and checked again later.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet: get(claim(), nil),
+						MockCreate: func(ctx context.Context, obj client.Object, _ ...client.CreateOption) error {
+							return unexpected("Create")(ctx, obj)
+						},
+						MockStatusUpdate: wantStatus(t, claim(withConditions(xpv1.ReconcileError(errors.Wrap(errors.Errorf(errForbidden, namespace, "team-a"), "no RoleBinding"))))),
+					}
+				},
+				authorizer: AuthorizerFn(func(_ context.Context, ns, pc string) (bool, string, error) {
+					return false, "no RoleBinding", nil
+				}),
+			},
+			want: want{r: reconcile.Result{RequeueAfter: forbiddenWait}},
+		},
+		"ReviewAccessError": {
+			reason: "An error telling whether a claim may use its ProviderConfig should be reported.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet:          get(claim(), nil),
+						MockStatusUpdate: wantStatus(t, claim(withConditions(xpv1.ReconcileError(errBoom)))),
+					}
+				},
+				authorizer: AuthorizerFn(func(context.Context, string, string) (bool, string, error) { return false, "", errBoom }),
+			},
+		},
+		"Create": {
+			reason: "A ZeebeCluster that writes its connection secret to the claim's namespace should be created for a new claim.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet: get(claim(), nil),
+						MockCreate: func(ctx context.Context, obj client.Object, _ ...client.CreateOption) error {
+							return wantCluster(t, "Create", cluster())(ctx, obj)
+						},
+						MockStatusUpdate: wantStatus(t, claim(withClusterRef(), withConditions(xpv1.ReconcileSuccess(), xpv1.Creating()))),
+					}
+				},
+				authorizer: AuthorizerFn(func(_ context.Context, ns, pc string) (bool, string, error) {
+					if ns != namespace || pc != "team-a" {
+						return false, "", errors.Errorf("unexpected namespace %q and ProviderConfig %q", ns, pc)
+					}
+					return true, "", nil
+				}),
+			},
+		},
+		"CreateError": {
+			reason: "An error creating the ZeebeCluster should be reported.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet:          get(claim(), nil),
+						MockCreate:       test.NewMockCreateFn(errBoom),
+						MockStatusUpdate: wantStatus(t, claim(withConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errApplyCluster))))),
+					}
+				},
+				authorizer: AuthorizerFn(allow),
+			},
+		},
+		"UpToDate": {
+			reason: "A ZeebeCluster that is configured as claimed, including late initialized parameters, should not be updated, and its status should be reported.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					zb := cluster(
+						withForProvider(v1alpha1.ZeebeClusterParameters{PlanName: "Development", ChannelName: "Stable", Region: "Europe West 1D"}),
+						withStatus(observed, xpv1.Available()),
+					)
+					return &test.MockClient{
+						MockGet: get(claim(), zb),
+						MockUpdate: func(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							return unexpected("Update")(ctx, obj)
+						},
+						MockStatusUpdate: wantStatus(t, claim(withClusterRef(), withAtProvider(observed), withConditions(xpv1.ReconcileSuccess(), xpv1.Available()))),
+					}
+				},
+				authorizer: AuthorizerFn(allow),
+			},
+		},
+		"Update": {
+			reason: "A ZeebeCluster whose parameters differ from those claimed should be updated.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet: get(claim(), cluster(withForProvider(v1alpha1.ZeebeClusterParameters{PlanName: "Production S", Region: "Europe West 1D"}))),
+						MockUpdate: func(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							return wantCluster(t, "Update", cluster())(ctx, obj)
+						},
+						MockStatusUpdate: wantStatus(t, claim(withClusterRef(), withConditions(xpv1.ReconcileSuccess(), xpv1.Creating()))),
+					}
+				},
+				authorizer: AuthorizerFn(allow),
+			},
+		},
+		"Delete": {
+			reason: "The ZeebeCluster of a deleted claim should be deleted, and the claim's finalizer removed.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet: get(claim(withDeletionTimestamp()), cluster()),
+						MockDelete: func(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+							return wantCluster(t, "Delete", cluster())(ctx, obj)
+						},
+					}
+				},
+			},
+		},
+		"DeleteError": {
+			reason: "An error deleting the ZeebeCluster of a deleted claim should be reported, and the finalizer kept.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{
+						MockGet:          get(claim(withDeletionTimestamp()), cluster()),
+						MockDelete:       test.NewMockDeleteFn(errBoom),
+						MockStatusUpdate: wantStatus(t, claim(withDeletionTimestamp(), withConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errDeleteCluster))))),
+					}
+				},
+				finalizer: resource.FinalizerFns{RemoveFinalizerFn: func(context.Context, resource.Object) error { return errors.New("unexpected call to RemoveFinalizer") }},
+			},
+		},
+		"RemoveFinalizerError": {
+			reason: "An error removing the finalizer of a deleted claim should be returned.",
+			args: args{
+				kube: func(t *testing.T) client.Client {
+					return &test.MockClient{MockGet: get(claim(withDeletionTimestamp()), nil)}
+				},
+				finalizer: resource.FinalizerFns{RemoveFinalizerFn: func(context.Context, resource.Object) error { return errBoom }},
+			},
+			want: want{err: errors.Wrap(errBoom, errRemoveFinalizer)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := tc.args.finalizer
+			if f == nil {
+				f = finalizer
+			}
+			o := []ReconcilerOption{WithFinalizer(f)}
+			if tc.args.authorizer != nil {
+				o = append(o, WithAuthorizer(tc.args.authorizer))
+			}
+			r := NewReconciler(tc.args.kube(t), o...)
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: claimName}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequestForClaim(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		zb     *v1alpha1.ZeebeCluster
+		want   []reconcile.Request
+	}{
+		"Bound": {
+			reason: "A ZeebeCluster bound to a claim should be mapped to a request for that claim.",
+			zb:     cluster(),
+			want:   []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: claimName}}},
+		},
+		"Unbound": {
+			reason: "A ZeebeCluster that is not bound to a claim should not be mapped to any request.",
+			zb:     &v1alpha1.ZeebeCluster{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := requestForClaim(tc.zb)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrequestForClaim(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSubjectAccessReviewer(t *testing.T) {
+	type want struct {
+		ok     bool
+		reason string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"Allowed": {
+			reason: "The use of a ProviderConfig should be reviewed for the service accounts of the namespace.",
+			kube: &test.MockClient{
+				MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					sar := obj.(*authorizationv1.SubjectAccessReview)
+					wantSpec := authorizationv1.SubjectAccessReviewSpec{
+						Groups: []string{"system:serviceaccounts:" + namespace},
+						ResourceAttributes: &authorizationv1.ResourceAttributes{
+							Namespace: namespace,
+							Verb:      "use",
+							Group:     "camunda.crossplane.io",
+							Resource:  "providerconfigs",
+							Name:      "team-a",
+						},
+					}
+					if diff := cmp.Diff(wantSpec, sar.Spec); diff != "" {
+						return errors.Errorf("SubjectAccessReview spec: -want, +got:\n%s", diff)
+					}
+					sar.Status.Allowed = true
+					return nil
+				},
+			},
+			want: want{ok: true},
+		},
+		"Denied": {
+			reason: "The reason the use of a ProviderConfig was denied should be returned.",
+			kube: &test.MockClient{
+				MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+					obj.(*authorizationv1.SubjectAccessReview).Status.Reason = "no RoleBinding"
+					return nil
+				}),
+			},
+			want: want{reason: "no RoleBinding"},
+		},
+		"CreateError": {
+			reason: "An error creating the SubjectAccessReview should be returned.",
+			kube:   &test.MockClient{MockCreate: test.NewMockCreateFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errReviewAccess)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, reason, err := NewSubjectAccessReviewer(tc.kube).MayUse(context.Background(), namespace, "team-a")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMayUse(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nMayUse(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, reason); diff != "" {
+				t.Errorf("\n%s\nMayUse(...): -want reason, +got reason:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,742 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package console implements a fake Camunda Cloud Console API, so that the
+// provider can be tested and developed without a Camunda Cloud account.
+//
+// The fake keeps its state in memory. It issues access tokens for one set of
+// client credentials, and clusters move from Creating to Healthy, and from
+// Deleting to gone, after configurable delays. Faults such as latency, server
+// errors, throttling, expiring access tokens and partially created clusters
+// can be injected to test how clients cope with an outage.
+package console
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+const (
+	// LoginPath is the path of the fake's OAuth token endpoint.
+	LoginPath = "/oauth/token"
+
+	// DefaultClientID and DefaultClientSecret are the client credentials the
+	// fake accepts unless configured otherwise.
+	DefaultClientID     = "fake-client-id"
+	DefaultClientSecret = "fake-client-secret"
+
+	// DefaultOrganizationID is the organization the fake's access tokens are
+	// issued for unless configured otherwise.
+	DefaultOrganizationID = "fake-organization"
+
+	// DefaultTokenTTL is how long access tokens are valid for.
+	DefaultTokenTTL = time.Hour
+
+	// DefaultCreatingFor and DefaultDeletingFor are how long clusters are
+	// creating and deleting for.
+	DefaultCreatingFor = 10 * time.Second
+	DefaultDeletingFor = 5 * time.Second
+)
+
+// Cluster readiness reported by the fake.
+const (
+	StatusCreating = "Creating"
+	StatusHealthy  = "Healthy"
+	StatusDeleting = "Deleting"
+)
+
+const (
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	claimOrganizationID = camunda.ClaimOrganizationID
+	audience            = "api.cloud.camunda.io"
+	domain              = "fake.camunda.local"
+)
+
+// An Option configures a Console.
+type Option func(c *Console)
+
+// WithCredentials configures the client credentials that the fake issues
+// access tokens for.
+func WithCredentials(clientID, clientSecret string) Option {
+	return func(c *Console) {
+		c.clientID = clientID
+		c.clientSecret = clientSecret
+	}
+}
+
+// WithOrganization configures the organization that access tokens are issued
+// for.
+func WithOrganization(id string) Option {
+	return func(c *Console) {
+		c.organization = id
+	}
+}
+
+// WithTokenTTL configures how long access tokens are valid for.
+func WithTokenTTL(d time.Duration) Option {
+	return func(c *Console) {
+		c.tokenTTL = d
+	}
+}
+
+// WithCreatingFor configures how long new clusters are creating for before
+// they become healthy.
+func WithCreatingFor(d time.Duration) Option {
+	return func(c *Console) {
+		c.creatingFor = d
+	}
+}
+
+// WithDeletingFor configures how long deleted clusters are deleting for
+// before they are gone.
+func WithDeletingFor(d time.Duration) Option {
+	return func(c *Console) {
+		c.deletingFor = d
+	}
+}
+
+// WithParams configures the channels, generations, plans and regions that
+// clusters may be created with.
+func WithParams(p cc.ClusterParams) Option {
+	return func(c *Console) {
+		c.params = p
+	}
+}
+
+// WithClock configures the function the fake tells the time with, so that
+// tests can move clusters through their states without waiting.
+func WithClock(now func() time.Time) Option {
+	return func(c *Console) {
+		c.now = now
+	}
+}
+
+// DefaultParams returns the cluster parameters the fake offers unless
+// configured otherwise.
+func DefaultParams() cc.ClusterParams {
+	g100 := cc.Generation{Id: "generation-zeebe-1-0-0", Name: "Zeebe 1.0.0"}
+	g110 := cc.Generation{Id: "generation-zeebe-1-1-0", Name: "Zeebe 1.1.0"}
+	g120 := cc.Generation{Id: "generation-zeebe-1-2-0-alpha1", Name: "Zeebe 1.2.0-alpha1"}
+	return cc.ClusterParams{
+		Channels: []cc.Channel{
+			{Id: "channel-stable", Name: "Stable", IsDefault: true, DefaultGeneration: g100, AllowedGeneration: []cc.Generation{g100, g110}},
+			{Id: "channel-alpha", Name: "Alpha", DefaultGeneration: g120, AllowedGeneration: []cc.Generation{g110, g120}},
+		},
+		ClusterPlanTypes: []cc.ClusterPlantType{
+			{Id: "plan-development", Name: "Development"},
+			{Id: "plan-production-s", Name: "Production S"},
+		},
+		Regions: []cc.Region{
+			{Id: "region-europe-west-1d", Name: "Europe West 1D", Region: "europe-west1", Zone: "europe-west1-d"},
+			{Id: "region-us-east-1b", Name: "US East 1B", Region: "us-east1", Zone: "us-east1-b"},
+		},
+	}
+}
+
+type cluster struct {
+	cc.Cluster
+
+	created     time.Time
+	deleted     time.Time
+	lastUpgrade time.Time
+	clients     map[string]*zeebeClient
+}
+
+type zeebeClient struct {
+	id      string
+	uuid    string
+	secret  string
+	name    string
+	created time.Time
+}
+
+// A Console is a fake Camunda Cloud Console API, served together with its
+// OAuth token endpoint at LoginPath. It is an http.Handler.
+type Console struct {
+	clientID     string
+	clientSecret string
+	organization string
+	tokenTTL     time.Duration
+	creatingFor  time.Duration
+	deletingFor  time.Duration
+	params       cc.ClusterParams
+	now          func() time.Time
+
+	mu       sync.Mutex
+	faults   Faults
+	tokens   map[string]time.Time
+	clusters map[string]*cluster
+}
+
+// New returns a fake Console API configured by the supplied options.
+func New(o ...Option) *Console {
+	c := &Console{
+		clientID:     DefaultClientID,
+		clientSecret: DefaultClientSecret,
+		organization: DefaultOrganizationID,
+		tokenTTL:     DefaultTokenTTL,
+		creatingFor:  DefaultCreatingFor,
+		deletingFor:  DefaultDeletingFor,
+		params:       DefaultParams(),
+		now:          time.Now,
+		tokens:       map[string]time.Time{},
+		clusters:     map[string]*cluster{},
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// Clusters returns all clusters that exist, including those that are being
+// deleted, ordered by name and then ID.
+func (c *Console) Clusters() []cc.Cluster {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l := make([]cc.Cluster, 0, len(c.clusters))
+	for _, cl := range c.live() {
+		l = append(l, cl.Cluster)
+	}
+	return l
+}
+
+// UpgradeCluster moves the supplied cluster to the supplied generation of its
+// channel, the way Camunda upgrades clusters.
+func (c *Console) UpgradeCluster(id, generationID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(id)
+	if !ok {
+		return fmt.Errorf("cluster %s not found", id)
+	}
+	channel, _ := c.channel(cl.Channel.Id)
+	g, ok := allowedGeneration(channel, generationID)
+	if !ok {
+		return fmt.Errorf("generation %q is not allowed on channel %q", generationID, channel.Name)
+	}
+	cl.Generation = g
+	cl.lastUpgrade = c.now()
+	return nil
+}
+
+// ServeHTTP serves the fake Console API.
+func (c *Console) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.fail(w) {
+		return
+	}
+	if r.URL.Path == LoginPath {
+		c.login(w, r)
+		return
+	}
+	if !c.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid or expired access token")
+		return
+	}
+	defer c.served()
+
+	// Paths are /clusters[/parameters|/{id}[/clients[/{clientId}]]].
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "clusters":
+		switch r.Method {
+		case http.MethodGet:
+			c.listClusters(w)
+		case http.MethodPost:
+			c.createCluster(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(parts) == 2 && parts[0] == "clusters" && parts[1] == "parameters":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, c.params)
+	case len(parts) == 2 && parts[0] == "clusters":
+		switch r.Method {
+		case http.MethodGet:
+			c.getCluster(w, parts[1])
+		case http.MethodDelete:
+			c.deleteCluster(w, parts[1])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(parts) == 3 && parts[0] == "clusters" && parts[2] == "clients":
+		switch r.Method {
+		case http.MethodGet:
+			c.listClients(w, parts[1])
+		case http.MethodPost:
+			c.createClient(w, r, parts[1])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(parts) == 4 && parts[0] == "clusters" && parts[2] == "clients":
+		switch r.Method {
+		case http.MethodGet:
+			c.getClient(w, parts[1], parts[3])
+		case http.MethodDelete:
+			c.deleteClient(w, parts[1], parts[3])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+type loginRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	SubjectToken string `json:"subject_token"`
+}
+
+type loginResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+// login issues access tokens for the fake's client credentials, or for any
+// subject token presented in an RFC 8693 token exchange.
+func (c *Console) login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	lr, err := readLogin(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch lr.GrantType {
+	case grantTypeClientCredentials:
+		if lr.ClientID != c.clientID || lr.ClientSecret != c.clientSecret {
+			writeError(w, http.StatusUnauthorized, "access_denied")
+			return
+		}
+	case grantTypeTokenExchange:
+		if lr.SubjectToken == "" {
+			writeError(w, http.StatusBadRequest, "subject_token is required")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiry := c.now().Add(c.tokenTTL)
+	token := c.token(expiry)
+	c.tokens[token] = expiry
+	writeJSON(w, http.StatusOK, loginResponse{AccessToken: token, ExpiresIn: int(c.tokenTTL.Seconds()), TokenType: "Bearer"})
+}
+
+func readLogin(r *http.Request) (loginRequest, error) {
+	lr := loginRequest{}
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mt == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			return lr, err
+		}
+		lr.GrantType = r.PostForm.Get("grant_type")
+		lr.ClientID = r.PostForm.Get("client_id")
+		lr.ClientSecret = r.PostForm.Get("client_secret")
+		lr.SubjectToken = r.PostForm.Get("subject_token")
+		return lr, nil
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return lr, err
+	}
+	return lr, json.Unmarshal(b, &lr)
+}
+
+// token returns an unsigned JWT that expires at the supplied time. The
+// provider reads the organization and expiry from its claims.
+func (c *Console) token(expiry time.Time) string {
+	enc := base64.RawURLEncoding
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":               "fake-console",
+		"aud":               audience,
+		"sub":               c.clientID,
+		"exp":               expiry.Unix(),
+		"jti":               newID(),
+		claimOrganizationID: c.organization,
+	})
+	return enc.EncodeToString(header) + "." + enc.EncodeToString(claims) + "." + enc.EncodeToString([]byte("fake"))
+}
+
+func (c *Console) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiry, ok := c.tokens[token]
+	return ok && c.now().Before(expiry)
+}
+
+// live removes clusters whose deletion completed, and returns the others
+// ordered by name and then ID. The caller must hold the lock.
+func (c *Console) live() []*cluster {
+	now := c.now()
+	l := make([]*cluster, 0, len(c.clusters))
+	for id, cl := range c.clusters {
+		if !cl.deleted.IsZero() && !now.Before(cl.deleted.Add(c.deletingFor)) {
+			delete(c.clusters, id)
+			continue
+		}
+		l = append(l, cl)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Name != l[j].Name {
+			return l[i].Name < l[j].Name
+		}
+		return l[i].ID < l[j].ID
+	})
+	return l
+}
+
+// get returns the supplied cluster, if it exists. The caller must hold the
+// lock.
+func (c *Console) get(id string) (*cluster, bool) {
+	for _, cl := range c.live() {
+		if cl.ID == id {
+			return cl, true
+		}
+	}
+	return nil, false
+}
+
+// status returns the readiness of the supplied cluster. The caller must hold
+// the lock.
+func (c *Console) status(cl *cluster) string {
+	switch {
+	case !cl.deleted.IsZero():
+		return StatusDeleting
+	case c.now().Before(cl.created.Add(c.creatingFor)):
+		return StatusCreating
+	default:
+		return StatusHealthy
+	}
+}
+
+func (c *Console) listClusters(w http.ResponseWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l := make([]cc.Cluster, 0, len(c.clusters))
+	for _, cl := range c.live() {
+		l = append(l, cl.Cluster)
+	}
+	writeJSON(w, http.StatusOK, l)
+}
+
+func (c *Console) getCluster(w http.ResponseWriter, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("cluster %s not found", id))
+		return
+	}
+
+	ready := c.status(cl)
+	component := ready
+	if ready == StatusDeleting {
+		component = "Unhealthy"
+	}
+	d := camunda.ClusterDetails{
+		Cluster: cl.Cluster,
+		Status: camunda.ClusterStatus{
+			ClusterStatus: cc.ClusterStatus{
+				Ready:          ready,
+				ZeebeStatus:    component,
+				OperateStatus:  component,
+				TaskListStatus: component,
+				ZeebeURL:       zeebeAddress(cl.ID),
+				OperateURL:     componentURL("operate", cl.ID),
+				TaskListURL:    componentURL("tasklist", cl.ID),
+			},
+			OptimizeStatus: component,
+			OptimizeURL:    componentURL("optimize", cl.ID),
+		},
+		Links: camunda.ClusterLinks{
+			Zeebe:    zeebeAddress(cl.ID),
+			Operate:  componentURL("operate", cl.ID),
+			Tasklist: componentURL("tasklist", cl.ID),
+			Optimize: componentURL("optimize", cl.ID),
+		},
+		Owner: camunda.ClusterOwner{ID: c.clientID, Name: c.clientID},
+	}
+	if !cl.lastUpgrade.IsZero() {
+		d.LastUpgrade = cl.lastUpgrade.UTC().Format(time.RFC3339)
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (c *Console) createCluster(w http.ResponseWriter, r *http.Request) {
+	in := cc.ClusterCreationParams{}
+	if err := readJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.ClusterName == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	channel, ok := c.channel(in.ChannelId)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown channel %q", in.ChannelId))
+		return
+	}
+	generation, ok := allowedGeneration(channel, in.GenerationId)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("generation %q is not allowed on channel %q", in.GenerationId, channel.Name))
+		return
+	}
+	plan, ok := c.plan(in.PlanTypeId)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown plan type %q", in.PlanTypeId))
+		return
+	}
+	region, ok := c.region(in.RegionId)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown region %q", in.RegionId))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	cl := &cluster{
+		Cluster: cc.Cluster{
+			ID:               newID(),
+			Name:             in.ClusterName,
+			Channel:          cc.Channel{Id: channel.Id, Name: channel.Name},
+			Generation:       generation,
+			Created:          now.UTC().Format(time.RFC3339),
+			K8sContext:       cc.K8sContext{ID: region.Id, Name: region.Name, Region: region.Region, Zone: region.Zone},
+			ClusterPlantType: plan,
+		},
+		created: now,
+		clients: map[string]*zeebeClient{},
+	}
+	c.clusters[cl.ID] = cl
+	if c.createdPartially() {
+		writeError(w, http.StatusInternalServerError, "injected failure after creating cluster")
+		return
+	}
+	writeJSON(w, http.StatusOK, cc.ClusterCreatedResponse{ClusterId: cl.ID})
+}
+
+func (c *Console) deleteCluster(w http.ResponseWriter, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("cluster %s not found", id))
+		return
+	}
+	// Deleting a cluster that is already being deleted succeeds.
+	if cl.deleted.IsZero() {
+		cl.deleted = c.now()
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Console) listClients(w http.ResponseWriter, clusterID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(clusterID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("cluster %s not found", clusterID))
+		return
+	}
+	l := make([]cc.ZeebeClientResponse, 0, len(cl.clients))
+	for _, zc := range cl.clients {
+		l = append(l, cc.ZeebeClientResponse{
+			ClientID:    zc.id,
+			UUID:        zc.uuid,
+			Name:        zc.name,
+			Created:     zc.created.UTC().Format(time.RFC3339),
+			CreatedBy:   c.clientID,
+			Permissions: []string{"zeebe"},
+		})
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	writeJSON(w, http.StatusOK, l)
+}
+
+func (c *Console) createClient(w http.ResponseWriter, r *http.Request, clusterID string) {
+	in := cc.ZeebeClientCreatePayload{}
+	if err := readJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.ClientName == "" {
+		writeError(w, http.StatusBadRequest, "clientName is required")
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(clusterID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("cluster %s not found", clusterID))
+		return
+	}
+	zc := &zeebeClient{id: newID(), uuid: newID(), secret: newID(), name: in.ClientName, created: c.now()}
+	cl.clients[zc.id] = zc
+	writeJSON(w, http.StatusOK, cc.ZeebeClientCreatedResponse{Name: zc.name, ClientID: zc.id, ClientSecret: zc.secret})
+}
+
+func (c *Console) getClient(w http.ResponseWriter, clusterID, clientID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(clusterID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("cluster %s not found", clusterID))
+		return
+	}
+	zc, ok := cl.clients[clientID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("client %s not found", clientID))
+		return
+	}
+	writeJSON(w, http.StatusOK, cc.ZeebeClientDetailsResponse{
+		Name:                        zc.name,
+		ZEEBEADDRESS:                zeebeAddress(cl.ID),
+		ZEEBECLIENTID:               zc.id,
+		ZEEBEAUTHORIZATIONSERVERURL: (&url.URL{Scheme: "https", Host: "login." + domain, Path: LoginPath}).String(),
+	})
+}
+
+func (c *Console) deleteClient(w http.ResponseWriter, clusterID, clientID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, ok := c.get(clusterID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("cluster %s not found", clusterID))
+		return
+	}
+	if _, ok := cl.clients[clientID]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("client %s not found", clientID))
+		return
+	}
+	delete(cl.clients, clientID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Console) channel(id string) (cc.Channel, bool) {
+	for _, ch := range c.params.Channels {
+		if ch.Id == id {
+			return ch, true
+		}
+	}
+	return cc.Channel{}, false
+}
+
+func (c *Console) plan(id string) (cc.ClusterPlantType, bool) {
+	for _, p := range c.params.ClusterPlanTypes {
+		if p.Id == id {
+			return p, true
+		}
+	}
+	return cc.ClusterPlantType{}, false
+}
+
+func (c *Console) region(id string) (cc.Region, bool) {
+	for _, r := range c.params.Regions {
+		if r.Id == id {
+			return r, true
+		}
+	}
+	return cc.Region{}, false
+}
+
+func allowedGeneration(ch cc.Channel, id string) (cc.Generation, bool) {
+	for _, g := range ch.AllowedGeneration {
+		if g.Id == id {
+			return g, true
+		}
+	}
+	return cc.Generation{Id: id}, false
+}
+
+func zeebeAddress(clusterID string) string {
+	return clusterID + ".zeebe." + domain + ":443"
+}
+
+func componentURL(component, clusterID string) string {
+	return "https://" + component + "." + domain + "/" + clusterID
+}
+
+// newID returns a random UUID.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+// clock is a time that tests move forward.
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newClient(srv *httptest.Server) *camunda.Client {
+	return camunda.New(camunda.WithHTTPClient(srv.Client()), camunda.WithEndpoints(srv.URL+LoginPath, srv.URL))
+}
+
+func TestLogin(t *testing.T) {
+	srv := httptest.NewServer(New(WithCredentials("id", "secret"), WithOrganization("org")))
+	defer srv.Close()
+
+	type want struct {
+		ok  bool
+		org string
+	}
+
+	cases := map[string]struct {
+		reason string
+		id     string
+		secret string
+		want   want
+	}{
+		"ValidCredentials": {
+			reason: "An access token for the configured organization should be issued for the configured credentials.",
+			id:     "id",
+			secret: "secret",
+			want:   want{ok: true, org: "org"},
+		},
+		"InvalidCredentials": {
+			reason: "No access token should be issued for other credentials.",
+			id:     "id",
+			secret: "guess",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(srv)
+			ok, _ := c.LoginWithContext(context.Background(), tc.id, tc.secret)
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nLoginWithContext(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if !ok {
+				return
+			}
+			org, err := c.OrganizationID()
+			if err != nil {
+				t.Fatalf("\n%s\nOrganizationID(): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.org, org); diff != "" {
+				t.Errorf("\n%s\nOrganizationID(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	clk := &clock{t: time.Now()}
+	srv := httptest.NewServer(New(WithTokenTTL(time.Minute), WithClock(clk.now)))
+	defer srv.Close()
+
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(context.Background(), DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+	if _, err := c.GetClustersWithContext(context.Background()); err != nil {
+		t.Errorf("GetClustersWithContext(...): a fresh access token should be accepted: %v", err)
+	}
+
+	clk.advance(time.Minute)
+	_, err := c.GetClustersWithContext(context.Background())
+	var he *camunda.HTTPError
+	if !errors.As(err, &he) || he.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetClustersWithContext(...): want HTTP 401 for an expired access token, got %v", err)
+	}
+}
+
+func TestClusterLifecycle(t *testing.T) {
+	clk := &clock{t: time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	con := New(WithCreatingFor(time.Minute), WithDeletingFor(time.Minute), WithClock(clk.now))
+	srv := httptest.NewServer(con)
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+
+	ready := func(id string) string {
+		t.Helper()
+		d, err := c.GetClusterDetailsWithContext(ctx, id)
+		if err != nil {
+			t.Fatalf("GetClusterDetailsWithContext(...): %v", err)
+		}
+		return d.Status.Ready
+	}
+
+	id, err := c.CreateClusterWithParamsAndContext(ctx, "cool", "Development", "Stable", "", "Europe West 1D")
+	if err != nil {
+		t.Fatalf("CreateClusterWithParamsAndContext(...): %v", err)
+	}
+	if diff := cmp.Diff(StatusCreating, ready(id)); diff != "" {
+		t.Errorf("a new cluster should be creating: -want, +got:\n%s", diff)
+	}
+
+	cl, err := c.GetClusterByNameWithContext(ctx, "cool")
+	if err != nil {
+		t.Fatalf("GetClusterByNameWithContext(...): %v", err)
+	}
+	want := cc.Cluster{
+		ID:               id,
+		Name:             "cool",
+		Channel:          cc.Channel{Id: "channel-stable", Name: "Stable"},
+		Generation:       cc.Generation{Id: "generation-zeebe-1-0-0", Name: "Zeebe 1.0.0"},
+		Created:          "2021-03-01T12:00:00Z",
+		K8sContext:       cc.K8sContext{ID: "region-europe-west-1d", Name: "Europe West 1D", Region: "europe-west1", Zone: "europe-west1-d"},
+		ClusterPlantType: cc.ClusterPlantType{Id: "plan-development", Name: "Development"},
+	}
+	if diff := cmp.Diff(want, cl); diff != "" {
+		t.Errorf("a new cluster should be created with the default generation of its channel: -want, +got:\n%s", diff)
+	}
+
+	clk.advance(time.Minute)
+	if diff := cmp.Diff(StatusHealthy, ready(id)); diff != "" {
+		t.Errorf("a cluster should become healthy once created: -want, +got:\n%s", diff)
+	}
+
+	if err := con.UpgradeCluster(id, "generation-zeebe-1-1-0"); err != nil {
+		t.Fatalf("UpgradeCluster(...): %v", err)
+	}
+	d, err := c.GetClusterDetailsWithContext(ctx, id)
+	if err != nil {
+		t.Fatalf("GetClusterDetailsWithContext(...): %v", err)
+	}
+	if diff := cmp.Diff("Zeebe 1.1.0", d.Generation.Name); diff != "" {
+		t.Errorf("an upgraded cluster should be on its new generation: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("2021-03-01T12:01:00Z", d.LastUpgrade); diff != "" {
+		t.Errorf("an upgraded cluster should report when it was upgraded: -want, +got:\n%s", diff)
+	}
+
+	if _, err := c.DeleteClusterWithContext(ctx, id); err != nil {
+		t.Fatalf("DeleteClusterWithContext(...): %v", err)
+	}
+	if diff := cmp.Diff(StatusDeleting, ready(id)); diff != "" {
+		t.Errorf("a deleted cluster should be deleting: -want, +got:\n%s", diff)
+	}
+	if _, err := c.DeleteClusterWithContext(ctx, id); err != nil {
+		t.Errorf("DeleteClusterWithContext(...): deleting a deleting cluster should succeed: %v", err)
+	}
+
+	clk.advance(time.Minute)
+	if diff := cmp.Diff(camunda.ClusterStatusNotFound, ready(id)); diff != "" {
+		t.Errorf("a deleted cluster should be gone once deleted: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]cc.Cluster{}, con.Clusters()); diff != "" {
+		t.Errorf("a deleted cluster should not be listed: -want, +got:\n%s", diff)
+	}
+}
+
+func TestClusterByID(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+
+	id, err := c.CreateClusterWithParamsAndContext(ctx, "cool", "plan-production-s", "channel-alpha", "generation-zeebe-1-1-0", "region-us-east-1b")
+	if err != nil {
+		t.Fatalf("CreateClusterWithParamsAndContext(...): %v", err)
+	}
+	d, err := c.GetClusterDetailsWithContext(ctx, id)
+	if err != nil {
+		t.Fatalf("GetClusterDetailsWithContext(...): %v", err)
+	}
+	got := []string{d.ClusterPlantType.Id, d.Channel.Id, d.Generation.Id, d.K8sContext.ID}
+	want := []string{"plan-production-s", "channel-alpha", "generation-zeebe-1-1-0", "region-us-east-1b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("a cluster should be created with the plan, channel, generation and region of the supplied IDs: -want, +got:\n%s", diff)
+	}
+}
+
+func TestCreateClusterNotOffered(t *testing.T) {
+	con := New()
+	srv := httptest.NewServer(con)
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason     string
+		plan       string
+		channel    string
+		generation string
+		region     string
+		want       string
+	}{
+		"UnknownPlan": {
+			reason: "A cluster should not be created with a plan that is not offered.",
+			plan:   "Enormous",
+			want:   "no cluster plan found with name: Enormous",
+		},
+		"UnknownChannel": {
+			reason:  "A cluster should not be created on a channel that is not offered.",
+			channel: "Nightly",
+			want:    "no channel found with name: Nightly",
+		},
+		"UnknownGeneration": {
+			reason:     "A cluster should not be created with a generation its channel does not allow.",
+			channel:    "Stable",
+			generation: "Zeebe 0.1.0",
+			want:       "no generation found with name: Zeebe 0.1.0",
+		},
+		"UnknownRegion": {
+			reason: "A cluster should not be created in a region that is not offered.",
+			region: "Moon 1A",
+			want:   "no region found with name: Moon 1A",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.CreateClusterWithParamsAndContext(ctx, "cool", tc.plan, tc.channel, tc.generation, tc.region)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCreateClusterWithParamsAndContext(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff([]cc.Cluster{}, con.Clusters()); diff != "" {
+				t.Errorf("\n%s\nCreateClusterWithParamsAndContext(...): -want clusters, +got clusters:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClients(t *testing.T) {
+	con := New()
+	srv := httptest.NewServer(con)
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+	id, err := c.CreateClusterWithParamsAndContext(ctx, "cool", "", "", "", "")
+	if err != nil {
+		t.Fatalf("CreateClusterWithParamsAndContext(...): %v", err)
+	}
+	token, _ := c.AccessToken()
+
+	do := func(method, path string, in, out interface{}) int {
+		t.Helper()
+		var body bytes.Buffer
+		if in != nil {
+			_ = json.NewEncoder(&body).Encode(in)
+		}
+		req, _ := http.NewRequest(method, srv.URL+path, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close() // nolint:errcheck
+		if out != nil {
+			_ = json.NewDecoder(resp.Body).Decode(out)
+		}
+		return resp.StatusCode
+	}
+
+	created := cc.ZeebeClientCreatedResponse{}
+	if code := do(http.MethodPost, "/clusters/"+id+"/clients", cc.ZeebeClientCreatePayload{ClientName: "worker"}, &created); code != http.StatusOK {
+		t.Fatalf("creating a client: want HTTP 200, got %d", code)
+	}
+	if created.ClientID == "" || created.ClientSecret == "" {
+		t.Errorf("a created client should have credentials, got %+v", created)
+	}
+
+	l := []cc.ZeebeClientResponse{}
+	do(http.MethodGet, "/clusters/"+id+"/clients", nil, &l)
+	if len(l) != 1 || l[0].Name != "worker" {
+		t.Errorf("the created client should be listed, got %+v", l)
+	}
+
+	details := cc.ZeebeClientDetailsResponse{}
+	do(http.MethodGet, "/clusters/"+id+"/clients/"+created.ClientID, nil, &details)
+	if diff := cmp.Diff(zeebeAddress(id), details.ZEEBEADDRESS); diff != "" {
+		t.Errorf("a client's details should include the cluster's gateway address: -want, +got:\n%s", diff)
+	}
+
+	if code := do(http.MethodDelete, "/clusters/"+id+"/clients/"+created.ClientID, nil, nil); code != http.StatusNoContent {
+		t.Errorf("deleting a client: want HTTP 204, got %d", code)
+	}
+	if code := do(http.MethodGet, "/clusters/"+id+"/clients/"+created.ClientID, nil, nil); code != http.StatusNotFound {
+		t.Errorf("getting a deleted client: want HTTP 404, got %d", code)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Faults make the fake misbehave the way the Console API does during an
+// outage. The counted faults affect that many of the following requests and
+// are used up by them; the zero value injects no faults.
+type Faults struct {
+	// Latency delays every request, including logins.
+	Latency time.Duration
+
+	// ServerErrors fails this many requests, including logins, with
+	// ServerErrorStatus, or HTTP 500 if that is not set.
+	ServerErrors      int
+	ServerErrorStatus int
+
+	// Throttled answers this many requests, including logins, with HTTP 429
+	// and a Retry-After of RetryAfter, rounded up to whole seconds.
+	Throttled  int
+	RetryAfter time.Duration
+
+	// ExpireTokensAfter expires all access tokens issued so far once this
+	// many more API requests were served.
+	ExpireTokensAfter int
+
+	// PartialCreates creates the clusters of this many create requests but
+	// fails the requests with HTTP 500, as if the Console failed after
+	// accepting them.
+	PartialCreates int
+}
+
+// WithFaults configures the faults the fake starts with.
+func WithFaults(f Faults) Option {
+	return func(c *Console) {
+		c.faults = f
+	}
+}
+
+// Inject replaces the faults of the fake with the supplied ones.
+func (c *Console) Inject(f Faults) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = f
+}
+
+// Faults returns the faults of the fake that are not used up yet.
+func (c *Console) Faults() Faults {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.faults
+}
+
+// ExpireTokens expires all access tokens issued so far.
+func (c *Console) ExpireTokens() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expireTokens()
+}
+
+// expireTokens expires all access tokens issued so far. The caller must hold
+// the lock.
+func (c *Console) expireTokens() {
+	now := c.now()
+	for token := range c.tokens {
+		c.tokens[token] = now
+	}
+}
+
+// fail delays a request by the injected latency and then answers it with an
+// injected server error or throttling, if any. It returns true if it did.
+func (c *Console) fail(w http.ResponseWriter) bool {
+	c.mu.Lock()
+	latency := c.faults.Latency
+	c.mu.Unlock()
+	time.Sleep(latency)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case c.faults.ServerErrors > 0:
+		c.faults.ServerErrors--
+		status := c.faults.ServerErrorStatus
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		writeError(w, status, "injected server error")
+		return true
+	case c.faults.Throttled > 0:
+		c.faults.Throttled--
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(c.faults.RetryAfter.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "injected throttling")
+		return true
+	}
+	return false
+}
+
+// served counts an API request towards the expiry of access tokens.
+func (c *Console) served() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.faults.ExpireTokensAfter == 0 {
+		return
+	}
+	c.faults.ExpireTokensAfter--
+	if c.faults.ExpireTokensAfter == 0 {
+		c.expireTokens()
+	}
+}
+
+// createdPartially returns true if the create request whose cluster was
+// just created should fail. The caller must hold the lock.
+func (c *Console) createdPartially() bool {
+	if c.faults.PartialCreates == 0 {
+		return false
+	}
+	c.faults.PartialCreates--
+	return true
+}
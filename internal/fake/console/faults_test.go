@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+// statusOf returns the HTTP status code the supplied error was caused by, or
+// HTTP 200 if there was no error.
+func statusOf(err error) int {
+	var he *camunda.HTTPError
+	var te *camunda.ThrottledError
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.As(err, &he):
+		return he.StatusCode
+	case errors.As(err, &te):
+		return te.StatusCode
+	}
+	return 0
+}
+
+func TestFaults(t *testing.T) {
+	type want struct {
+		// statuses of consecutive requests listing clusters.
+		statuses   []int
+		retryAfter time.Duration
+		faults     *Faults
+	}
+
+	cases := map[string]struct {
+		reason string
+		faults Faults
+		want   want
+	}{
+		"NoFaults": {
+			reason: "Requests should succeed when no faults are injected.",
+			want:   want{statuses: []int{http.StatusOK, http.StatusOK}},
+		},
+		"ServerErrors": {
+			reason: "A burst of requests should fail with HTTP 500, then requests should succeed again.",
+			faults: Faults{ServerErrors: 2},
+			want:   want{statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK}},
+		},
+		"ServerErrorStatus": {
+			reason: "A burst of requests should fail with the configured server error.",
+			faults: Faults{ServerErrors: 1, ServerErrorStatus: http.StatusBadGateway},
+			want:   want{statuses: []int{http.StatusBadGateway, http.StatusOK}},
+		},
+		"Throttled": {
+			reason: "Throttled requests should be answered with HTTP 429 and a Retry-After in whole seconds.",
+			faults: Faults{Throttled: 1, RetryAfter: 1500 * time.Millisecond},
+			want:   want{statuses: []int{http.StatusTooManyRequests, http.StatusOK}, retryAfter: 2 * time.Second},
+		},
+		"ExpireTokensAfter": {
+			reason: "Access tokens should expire once the configured number of requests was served.",
+			faults: Faults{ExpireTokensAfter: 2},
+			want:   want{statuses: []int{http.StatusOK, http.StatusOK, http.StatusUnauthorized}},
+		},
+		"Remaining": {
+			reason: "Faults that are not used up should be reported.",
+			faults: Faults{ServerErrors: 3, PartialCreates: 1},
+			want: want{
+				statuses: []int{http.StatusInternalServerError},
+				faults:   &Faults{ServerErrors: 2, PartialCreates: 1},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			con := New()
+			srv := httptest.NewServer(con)
+			defer srv.Close()
+
+			ctx := context.Background()
+			c := newClient(srv)
+			if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+				t.Fatalf("LoginWithContext(...): %v", err)
+			}
+			con.Inject(tc.faults)
+
+			got := make([]int, 0, len(tc.want.statuses))
+			var retryAfter time.Duration
+			for range tc.want.statuses {
+				_, err := c.GetClustersWithContext(ctx)
+				got = append(got, statusOf(err))
+				if d, ok := camunda.RetryAfter(err); ok {
+					retryAfter = d
+				}
+			}
+			if diff := cmp.Diff(tc.want.statuses, got); diff != "" {
+				t.Errorf("\n%s\nGetClustersWithContext(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.retryAfter, retryAfter); diff != "" {
+				t.Errorf("\n%s\nGetClustersWithContext(...): -want retry after, +got retry after:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.faults == nil {
+				return
+			}
+			if diff := cmp.Diff(*tc.want.faults, con.Faults()); diff != "" {
+				t.Errorf("\n%s\nFaults(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv := httptest.NewServer(New(WithFaults(Faults{Latency: 50 * time.Millisecond})))
+	defer srv.Close()
+
+	start := time.Now()
+	if _, err := newClient(srv).LoginWithContext(context.Background(), DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("LoginWithContext(...): want a response delayed by at least 50ms, got one after %s", d)
+	}
+}
+
+func TestFailedLogin(t *testing.T) {
+	srv := httptest.NewServer(New(WithFaults(Faults{ServerErrors: 1})))
+	defer srv.Close()
+
+	c := newClient(srv)
+	if ok, _ := c.LoginWithContext(context.Background(), DefaultClientID, DefaultClientSecret); ok {
+		t.Errorf("LoginWithContext(...): want a login to fail during a burst of server errors")
+	}
+	if ok, err := c.LoginWithContext(context.Background(), DefaultClientID, DefaultClientSecret); !ok {
+		t.Errorf("LoginWithContext(...): want a login to succeed after a burst of server errors: %v", err)
+	}
+}
+
+func TestExpireTokens(t *testing.T) {
+	con := New()
+	srv := httptest.NewServer(con)
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+	con.ExpireTokens()
+	if _, err := c.GetClustersWithContext(ctx); statusOf(err) != http.StatusUnauthorized {
+		t.Errorf("GetClustersWithContext(...): want HTTP 401 for an expired access token, got %v", err)
+	}
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+	if _, err := c.GetClustersWithContext(ctx); err != nil {
+		t.Errorf("GetClustersWithContext(...): want a new access token to be accepted: %v", err)
+	}
+}
+
+func TestPartialCreates(t *testing.T) {
+	con := New(WithFaults(Faults{PartialCreates: 1}))
+	srv := httptest.NewServer(con)
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newClient(srv)
+	if _, err := c.LoginWithContext(ctx, DefaultClientID, DefaultClientSecret); err != nil {
+		t.Fatalf("LoginWithContext(...): %v", err)
+	}
+	if _, err := c.CreateClusterWithParamsAndContext(ctx, "cool", "", "", "", ""); statusOf(err) != http.StatusInternalServerError {
+		t.Errorf("CreateClusterWithParamsAndContext(...): want HTTP 500 for a partially created cluster, got %v", err)
+	}
+	if cl := con.Clusters(); len(cl) != 1 || cl[0].Name != "cool" {
+		t.Errorf("a partially created cluster should exist, got %+v", cl)
+	}
+}
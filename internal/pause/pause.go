@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pause lets reconciliation of individual managed resources be paused
+// with an annotation.
+package pause
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	// AnnotationKeyPaused pauses reconciliation of a managed resource while it
+	// is set to "true".
+	AnnotationKeyPaused = "crossplane.io/paused"
+
+	// ReasonReconcilePaused is the reason of the Synced condition of a managed
+	// resource whose reconciliation is paused.
+	ReasonReconcilePaused xpv1.ConditionReason = "ReconcilePaused"
+)
+
+const (
+	timeout = 1 * time.Minute
+
+	errUpdateStatus = "cannot update status of paused managed resource"
+)
+
+// IsPaused returns true if reconciliation of the supplied object is paused.
+func IsPaused(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyPaused] == "true"
+}
+
+// Paused returns a condition indicating that reconciliation of a managed
+// resource is paused.
+func Paused() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReconcilePaused,
+		Message:            "Reconciliation is paused by the " + AnnotationKeyPaused + " annotation",
+	}
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// A Reconciler skips the wrapped reconciler for managed resources whose
+// reconciliation is paused, and records that they are paused in their Synced
+// condition. It works with managed resources of any kind.
+type Reconciler struct {
+	client     client.Client
+	newManaged func() resource.Managed
+	wrapped    reconcile.Reconciler
+	log        logging.Logger
+}
+
+// NewReconciler wraps the supplied reconciler of the supplied kind of managed
+// resource so that it is skipped while reconciliation is paused.
+func NewReconciler(m manager.Manager, of resource.ManagedKind, wrapped reconcile.Reconciler, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: m.GetClient(),
+		newManaged: func() resource.Managed {
+			return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+		},
+		wrapped: wrapped,
+		log:     logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Reconcile the supplied request, unless reconciliation of the requested
+// managed resource is paused.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	getCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if err := r.client.Get(getCtx, req.NamespacedName, mg); err != nil || !IsPaused(mg) {
+		// The wrapped reconciler gets the managed resource again, and knows
+		// how to deal with errors doing so.
+		return r.wrapped.Reconcile(ctx, req)
+	}
+
+	r.log.Debug("Reconciliation is paused", "request", req)
+	if mg.GetCondition(xpv1.TypeSynced).Equal(Paused()) {
+		return reconcile.Result{}, nil
+	}
+	mg.SetConditions(Paused())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(getCtx, mg), errUpdateStatus)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type reconcilerFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcilerFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	wrappedResult := reconcile.Result{Requeue: true}
+
+	withAnnotations := func(a map[string]string, c ...xpv1.Condition) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			obj.SetAnnotations(a)
+			obj.(resource.Managed).SetConditions(c...)
+			return nil
+		}
+	}
+	paused := map[string]string{AnnotationKeyPaused: "true"}
+
+	type want struct {
+		result  reconcile.Result
+		err     error
+		wrapped bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"GetError": {
+			reason: "The wrapped reconciler should deal with errors getting the managed resource.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{result: wrappedResult, wrapped: true},
+		},
+		"NotPaused": {
+			reason: "A managed resource that is not paused should be reconciled.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil, withAnnotations(map[string]string{AnnotationKeyPaused: "false"}))},
+			want:   want{result: wrappedResult, wrapped: true},
+		},
+		"Paused": {
+			reason: "A paused managed resource should not be reconciled, and its Synced condition should say so.",
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, withAnnotations(paused)),
+				MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					if c := obj.(resource.Managed).GetCondition(xpv1.TypeSynced); !c.Equal(Paused()) {
+						return errors.Errorf("want condition %v, got %v", Paused(), c)
+					}
+					return nil
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"AlreadyPaused": {
+			reason: "The status of a managed resource that is known to be paused should not be updated again.",
+			kube: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withAnnotations(paused, Paused())),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"StatusUpdateError": {
+			reason: "Errors recording that a managed resource is paused should be returned.",
+			kube: &test.MockClient{
+				MockGet:          test.NewMockGetFn(nil, withAnnotations(paused)),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			want: want{result: reconcile.Result{}, err: errors.Wrap(errBoom, errUpdateStatus)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wrapped := false
+			r := &Reconciler{
+				client:     tc.kube,
+				newManaged: func() resource.Managed { return &fake.Managed{} },
+				wrapped: reconcilerFn(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					wrapped = true
+					return wrappedResult, nil
+				}),
+				log: logging.NewNopLogger(),
+			}
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wrapped, wrapped); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want wrapped reconciler called, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
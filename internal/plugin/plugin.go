@@ -0,0 +1,295 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements the commands of the kubectl-camunda plugin, which
+// inspects the clusters of a Camunda Cloud organization and imports them as
+// ZeebeClusters.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/clients/camunda"
+)
+
+const (
+	errGetClusters       = "cannot list clusters of the organization"
+	errGetClusterParams  = "cannot get cluster parameters"
+	errListZeebeClusters = "cannot list ZeebeClusters"
+	errNotFound          = "cluster %q does not exist"
+	errManaged           = "cluster %q is already managed by ZeebeCluster %q"
+	errInvalidName       = "cluster %q cannot be imported because its name is not a valid object name: %s"
+	errConvert           = "cannot convert ZeebeCluster"
+	errMarshal           = "cannot marshal ZeebeCluster"
+)
+
+// none is shown in place of values that are not set.
+const none = "<none>"
+
+// A Console lists the clusters of a Camunda Cloud organization and the
+// parameters that clusters may be created with.
+type Console interface {
+	GetClustersWithContext(ctx context.Context) ([]cc.Cluster, error)
+	GetClusterParamsWithContext(ctx context.Context) (*cc.ClusterParams, error)
+}
+
+var _ Console = &camunda.Client{}
+
+// A Cluster of the organization, and the ZeebeCluster that manages it.
+type Cluster struct {
+	cc.Cluster
+
+	// ManagedBy is the name of the ZeebeCluster that manages the cluster. It
+	// is empty if the cluster is not managed.
+	ManagedBy string
+}
+
+// ListClusters returns the clusters of the organization sorted by name, each
+// with the ZeebeCluster that manages it, if any. A ZeebeCluster manages the
+// cluster it is named after, or the cluster it has observed.
+func ListClusters(ctx context.Context, con Console, kube client.Reader) ([]Cluster, error) {
+	ccl, err := con.GetClustersWithContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClusters)
+	}
+	l := &v1alpha1.ZeebeClusterList{}
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListZeebeClusters)
+	}
+
+	byName := map[string]string{}
+	byID := map[string]string{}
+	for _, zb := range l.Items {
+		byName[zb.GetName()] = zb.GetName()
+		if id := zb.Status.AtProvider.ClusterId; id != "" {
+			byID[id] = zb.GetName()
+		}
+	}
+
+	cls := make([]Cluster, len(ccl))
+	for i, cl := range ccl {
+		cls[i] = Cluster{Cluster: cl, ManagedBy: byID[cl.ID]}
+		if cls[i].ManagedBy == "" {
+			cls[i].ManagedBy = byName[cl.Name]
+		}
+	}
+	sort.SliceStable(cls, func(i, j int) bool { return cls[i].Name < cls[j].Name })
+	return cls, nil
+}
+
+// PrintClusters writes the supplied clusters to w as a table.
+func PrintClusters(w io.Writer, cls []Cluster) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tID\tPLAN\tCHANNEL\tGENERATION\tREGION\tZEEBECLUSTER")
+	for _, cl := range cls {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", cl.Name, cl.ID,
+			orNone(cl.ClusterPlantType.Name), orNone(cl.Channel.Name), orNone(cl.Generation.Name),
+			orNone(cl.K8sContext.Name), orNone(cl.ManagedBy))
+	}
+	return tw.Flush()
+}
+
+// ImportOptions configure the ZeebeClusters that clusters are imported as.
+type ImportOptions struct {
+	// ProviderConfig the ZeebeClusters use.
+	ProviderConfig string
+
+	// ConnectionSecretNamespace is the namespace the connection secrets of
+	// the ZeebeClusters are written to, named after the cluster with a
+	// -zeebe suffix. No connection secrets are written if it is empty.
+	ConnectionSecretNamespace string
+
+	// DeletionPolicy of the ZeebeClusters.
+	DeletionPolicy xpv1.DeletionPolicy
+
+	// ObserveOnly imports the clusters as observe-only ZeebeClusters, which
+	// never change or delete them.
+	ObserveOnly bool
+}
+
+// Import returns a ZeebeCluster for each of the named clusters, or for every
+// cluster that is not managed yet if no names are supplied. It returns an
+// error if a named cluster does not exist or is managed already. Since a
+// ZeebeCluster manages the cluster it is named after, clusters whose names
+// are not valid object names cannot be imported. Naming such a cluster is an
+// error; if no names are supplied such clusters are skipped, and their names
+// are returned.
+func Import(cls []Cluster, names []string, o ImportOptions) ([]*v1alpha1.ZeebeCluster, []string, error) {
+	zbs := []*v1alpha1.ZeebeCluster{}
+	if len(names) == 0 {
+		var skipped []string
+		for _, cl := range cls {
+			if cl.ManagedBy != "" {
+				continue
+			}
+			if len(validation.IsDNS1123Subdomain(cl.Name)) > 0 {
+				skipped = append(skipped, cl.Name)
+				continue
+			}
+			zbs = append(zbs, ZeebeClusterFor(cl.Cluster, o))
+		}
+		return zbs, skipped, nil
+	}
+
+	for _, name := range names {
+		cl, ok := find(cls, name)
+		if !ok {
+			return nil, nil, errors.Errorf(errNotFound, name)
+		}
+		if cl.ManagedBy != "" {
+			return nil, nil, errors.Errorf(errManaged, name, cl.ManagedBy)
+		}
+		if msgs := validation.IsDNS1123Subdomain(cl.Name); len(msgs) > 0 {
+			return nil, nil, errors.Errorf(errInvalidName, cl.Name, strings.Join(msgs, "; "))
+		}
+		zbs = append(zbs, ZeebeClusterFor(cl.Cluster, o))
+	}
+	return zbs, nil, nil
+}
+
+func find(cls []Cluster, name string) (Cluster, bool) {
+	for _, cl := range cls {
+		if cl.Name == name {
+			return cl, true
+		}
+	}
+	return Cluster{}, false
+}
+
+// ZeebeClusterFor returns a ZeebeCluster that manages the supplied cluster as
+// it is.
+func ZeebeClusterFor(cl cc.Cluster, o ImportOptions) *v1alpha1.ZeebeCluster {
+	zb := &v1alpha1.ZeebeCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: v1alpha1.ZeebeClusterKind},
+		ObjectMeta: metav1.ObjectMeta{Name: cl.Name},
+		Spec: v1alpha1.ZeebeClusterSpec{
+			ForProvider: v1alpha1.ZeebeClusterParameters{
+				Region:         cl.K8sContext.Name,
+				ChannelName:    cl.Channel.Name,
+				GenerationName: cl.Generation.Name,
+				PlanName:       cl.ClusterPlantType.Name,
+			},
+		},
+	}
+	if o.ProviderConfig != "" {
+		zb.Spec.ProviderConfigReference = &xpv1.Reference{Name: o.ProviderConfig}
+	}
+	if o.ConnectionSecretNamespace != "" {
+		zb.Spec.WriteConnectionSecretToReference = &xpv1.SecretReference{Namespace: o.ConnectionSecretNamespace, Name: cl.Name + "-zeebe"}
+	}
+	zb.Spec.DeletionPolicy = o.DeletionPolicy
+	if o.ObserveOnly {
+		zb.Spec.ManagementPolicy = v1alpha1.ManagementObserveOnly
+	}
+	return zb
+}
+
+// PrintZeebeClusters writes the supplied ZeebeClusters to w as a stream of
+// YAML documents that is ready to apply. Their status and other fields that
+// are set by the API server are left out.
+func PrintZeebeClusters(w io.Writer, zbs []*v1alpha1.ZeebeCluster) error {
+	for i, zb := range zbs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(zb)
+		if err != nil {
+			return errors.Wrap(err, errConvert)
+		}
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u, "status")
+		y, err := yaml.Marshal(u)
+		if err != nil {
+			return errors.Wrap(err, errMarshal)
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCatalog returns the plans, channels, generations and regions that
+// clusters may be created with.
+func GetCatalog(ctx context.Context, con Console) (*cc.ClusterParams, error) {
+	p, err := con.GetClusterParamsWithContext(ctx)
+	return p, errors.Wrap(err, errGetClusterParams)
+}
+
+// PrintCatalog writes the plans, channels, generations and regions of the
+// supplied cluster parameters to w as one table each. Each generation is
+// listed with the channels that offer it.
+func PrintCatalog(w io.Writer, p *cc.ClusterParams) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "PLAN\tID")
+	for _, pl := range p.ClusterPlanTypes {
+		fmt.Fprintf(tw, "%s\t%s\n", pl.Name, pl.Id)
+	}
+
+	fmt.Fprintln(tw, "\nCHANNEL\tID\tDEFAULT\tDEFAULT GENERATION")
+	for _, ch := range p.Channels {
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%s\n", ch.Name, ch.Id, ch.IsDefault, orNone(ch.DefaultGeneration.Name))
+	}
+
+	fmt.Fprintln(tw, "\nGENERATION\tID\tCHANNELS")
+	var generations []cc.Generation
+	channels := map[string][]string{}
+	for _, ch := range p.Channels {
+		for _, g := range ch.AllowedGeneration {
+			if _, ok := channels[g.Id]; !ok {
+				generations = append(generations, g)
+			}
+			channels[g.Id] = append(channels[g.Id], ch.Name)
+		}
+	}
+	for _, g := range generations {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", g.Name, g.Id, strings.Join(channels[g.Id], ","))
+	}
+
+	fmt.Fprintln(tw, "\nREGION\tID\tZONE")
+	for _, r := range p.Regions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Name, r.Id, orNone(r.Zone))
+	}
+
+	return tw.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return none
+	}
+	return s
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	cc "github.com/camunda-community-hub/camunda-cloud-go-client/pkg/cc/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/fake/console"
+)
+
+var errBoom = errors.New("boom")
+
+type mockConsole struct {
+	clusters []cc.Cluster
+	params   *cc.ClusterParams
+	err      error
+}
+
+func (m *mockConsole) GetClustersWithContext(_ context.Context) ([]cc.Cluster, error) {
+	return m.clusters, m.err
+}
+
+func (m *mockConsole) GetClusterParamsWithContext(_ context.Context) (*cc.ClusterParams, error) {
+	return m.params, m.err
+}
+
+func cluster(name, id string) cc.Cluster {
+	return cc.Cluster{
+		ID:               id,
+		Name:             name,
+		Channel:          cc.Channel{Id: "channel-stable", Name: "Stable"},
+		Generation:       cc.Generation{Id: "generation-zeebe-1-0-0", Name: "Zeebe 1.0.0"},
+		ClusterPlantType: cc.ClusterPlantType{Id: "plan-development", Name: "Development"},
+		K8sContext:       cc.K8sContext{ID: "region-europe-west-1d", Name: "Europe West 1D"},
+	}
+}
+
+func zeebeClusters(zbs ...v1alpha1.ZeebeCluster) test.MockListFn {
+	return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		obj.(*v1alpha1.ZeebeClusterList).Items = zbs
+		return nil
+	}
+}
+
+func TestListClusters(t *testing.T) {
+	observed := v1alpha1.ZeebeCluster{ObjectMeta: metav1.ObjectMeta{Name: "renamed"}}
+	observed.Status.AtProvider.ClusterId = "orders-id"
+
+	type want struct {
+		cls []Cluster
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		con    Console
+		kube   client.Reader
+		want   want
+	}{
+		"GetClustersError": {
+			reason: "Errors listing the clusters of the organization should be returned.",
+			con:    &mockConsole{err: errBoom},
+			want:   want{err: errors.Wrap(errBoom, errGetClusters)},
+		},
+		"ListZeebeClustersError": {
+			reason: "Errors listing ZeebeClusters should be returned.",
+			con:    &mockConsole{},
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errListZeebeClusters)},
+		},
+		"Managed": {
+			reason: "Clusters should be sorted by name and marked as managed by the ZeebeCluster named after them or that observed them.",
+			con:    &mockConsole{clusters: []cc.Cluster{cluster("payments", "payments-id"), cluster("orders", "orders-id"), cluster("billing", "billing-id")}},
+			kube: &test.MockClient{MockList: zeebeClusters(
+				v1alpha1.ZeebeCluster{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+				observed,
+			)},
+			want: want{cls: []Cluster{
+				{Cluster: cluster("billing", "billing-id")},
+				{Cluster: cluster("orders", "orders-id"), ManagedBy: "renamed"},
+				{Cluster: cluster("payments", "payments-id"), ManagedBy: "payments"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ListClusters(context.Background(), tc.con, tc.kube)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListClusters(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cls, got); diff != "" {
+				t.Errorf("\n%s\nListClusters(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrintClusters(t *testing.T) {
+	cls := []Cluster{
+		{Cluster: cluster("billing", "billing-id")},
+		{Cluster: cc.Cluster{ID: "orders-id", Name: "orders"}, ManagedBy: "orders"},
+	}
+	want := `NAME      ID           PLAN          CHANNEL   GENERATION    REGION           ZEEBECLUSTER
+billing   billing-id   Development   Stable    Zeebe 1.0.0   Europe West 1D   <none>
+orders    orders-id    <none>        <none>    <none>        <none>           orders
+`
+	b := &bytes.Buffer{}
+	if err := PrintClusters(b, cls); err != nil {
+		t.Fatalf("PrintClusters(...): %v", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("PrintClusters(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestImport(t *testing.T) {
+	cls := []Cluster{
+		{Cluster: cluster("billing", "billing-id")},
+		{Cluster: cluster("orders", "orders-id"), ManagedBy: "orders"},
+		{Cluster: cluster("payments", "payments-id")},
+		{Cluster: cluster("Legacy Cluster", "legacy-id")},
+	}
+	o := ImportOptions{ProviderConfig: "default"}
+
+	type args struct {
+		cls   []Cluster
+		names []string
+	}
+	type want struct {
+		zbs     []*v1alpha1.ZeebeCluster
+		skipped []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Named": {
+			reason: "A ZeebeCluster should be returned for each named cluster.",
+			args:   args{cls: cls, names: []string{"payments", "billing"}},
+			want: want{zbs: []*v1alpha1.ZeebeCluster{
+				ZeebeClusterFor(cluster("payments", "payments-id"), o),
+				ZeebeClusterFor(cluster("billing", "billing-id"), o),
+			}},
+		},
+		"NotFound": {
+			reason: "Importing a cluster that does not exist should fail.",
+			args:   args{cls: cls, names: []string{"shipping"}},
+			want:   want{err: errors.Errorf(errNotFound, "shipping")},
+		},
+		"Managed": {
+			reason: "Importing a cluster that is managed already should fail.",
+			args:   args{cls: cls, names: []string{"orders"}},
+			want:   want{err: errors.Errorf(errManaged, "orders", "orders")},
+		},
+		"InvalidName": {
+			reason: "Importing a cluster whose name is not a valid object name should fail.",
+			args:   args{cls: cls, names: []string{"Legacy Cluster"}},
+			want:   want{err: errors.Errorf(errInvalidName, "Legacy Cluster", strings.Join(validation.IsDNS1123Subdomain("Legacy Cluster"), "; "))},
+		},
+		"Unmanaged": {
+			reason: "A ZeebeCluster should be returned for every unmanaged cluster if none are named, skipping those with invalid names.",
+			args:   args{cls: cls},
+			want: want{
+				zbs: []*v1alpha1.ZeebeCluster{
+					ZeebeClusterFor(cluster("billing", "billing-id"), o),
+					ZeebeClusterFor(cluster("payments", "payments-id"), o),
+				},
+				skipped: []string{"Legacy Cluster"},
+			},
+		},
+		"AllManaged": {
+			reason: "No ZeebeClusters should be returned if every cluster is managed.",
+			args:   args{cls: cls[1:2]},
+			want:   want{zbs: []*v1alpha1.ZeebeCluster{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, skipped, err := Import(tc.args.cls, tc.args.names, o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nImport(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.zbs, got); diff != "" {
+				t.Errorf("\n%s\nImport(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.skipped, skipped); diff != "" {
+				t.Errorf("\n%s\nImport(...): -want skipped, +got skipped:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestZeebeClusterFor(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      ImportOptions
+		want   *v1alpha1.ZeebeCluster
+	}{
+		"Defaults": {
+			reason: "The ZeebeCluster should be named after the cluster and request its plan, channel, generation and region.",
+			want: &v1alpha1.ZeebeCluster{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "cc.camunda.crossplane.io/v1alpha1", Kind: "ZeebeCluster"},
+				ObjectMeta: metav1.ObjectMeta{Name: "orders"},
+				Spec: v1alpha1.ZeebeClusterSpec{
+					ForProvider: v1alpha1.ZeebeClusterParameters{
+						Region:         "Europe West 1D",
+						ChannelName:    "Stable",
+						GenerationName: "Zeebe 1.0.0",
+						PlanName:       "Development",
+					},
+				},
+			},
+		},
+		"Options": {
+			reason: "The ProviderConfig, connection secret, deletion policy and management policy should be set as configured.",
+			o: ImportOptions{
+				ProviderConfig:            "team-a",
+				ConnectionSecretNamespace: "crossplane-system",
+				DeletionPolicy:            xpv1.DeletionOrphan,
+				ObserveOnly:               true,
+			},
+			want: &v1alpha1.ZeebeCluster{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "cc.camunda.crossplane.io/v1alpha1", Kind: "ZeebeCluster"},
+				ObjectMeta: metav1.ObjectMeta{Name: "orders"},
+				Spec: v1alpha1.ZeebeClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{
+						ProviderConfigReference:          &xpv1.Reference{Name: "team-a"},
+						WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "orders-zeebe"},
+						DeletionPolicy:                   xpv1.DeletionOrphan,
+					},
+					ManagementPolicy: v1alpha1.ManagementObserveOnly,
+					ForProvider: v1alpha1.ZeebeClusterParameters{
+						Region:         "Europe West 1D",
+						ChannelName:    "Stable",
+						GenerationName: "Zeebe 1.0.0",
+						PlanName:       "Development",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ZeebeClusterFor(cluster("orders", "orders-id"), tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nZeebeClusterFor(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrintZeebeClusters(t *testing.T) {
+	o := ImportOptions{ProviderConfig: "default", DeletionPolicy: xpv1.DeletionOrphan}
+	zbs := []*v1alpha1.ZeebeCluster{
+		ZeebeClusterFor(cluster("billing", "billing-id"), o),
+		ZeebeClusterFor(cluster("orders", "orders-id"), o),
+	}
+	want := `apiVersion: cc.camunda.crossplane.io/v1alpha1
+kind: ZeebeCluster
+metadata:
+  name: billing
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    channelName: Stable
+    generationName: Zeebe 1.0.0
+    planName: Development
+    region: Europe West 1D
+  providerConfigRef:
+    name: default
+---
+apiVersion: cc.camunda.crossplane.io/v1alpha1
+kind: ZeebeCluster
+metadata:
+  name: orders
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    channelName: Stable
+    generationName: Zeebe 1.0.0
+    planName: Development
+    region: Europe West 1D
+  providerConfigRef:
+    name: default
+`
+	b := &bytes.Buffer{}
+	if err := PrintZeebeClusters(b, zbs); err != nil {
+		t.Fatalf("PrintZeebeClusters(...): %v", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("PrintZeebeClusters(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestGetCatalog(t *testing.T) {
+	p := console.DefaultParams()
+
+	type want struct {
+		p   *cc.ClusterParams
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		con    Console
+		want   want
+	}{
+		"GetClusterParamsError": {
+			reason: "Errors getting the cluster parameters should be returned.",
+			con:    &mockConsole{err: errBoom},
+			want:   want{err: errors.Wrap(errBoom, errGetClusterParams)},
+		},
+		"Success": {
+			reason: "The cluster parameters should be returned.",
+			con:    &mockConsole{params: &p},
+			want:   want{p: &p},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetCatalog(context.Background(), tc.con)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetCatalog(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, got); diff != "" {
+				t.Errorf("\n%s\nGetCatalog(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrintCatalog(t *testing.T) {
+	p := console.DefaultParams()
+	want := `PLAN           ID
+Development    plan-development
+Production S   plan-production-s
+
+CHANNEL   ID               DEFAULT   DEFAULT GENERATION
+Stable    channel-stable   true      Zeebe 1.0.0
+Alpha     channel-alpha    false     Zeebe 1.2.0-alpha1
+
+GENERATION           ID                              CHANNELS
+Zeebe 1.0.0          generation-zeebe-1-0-0          Stable
+Zeebe 1.1.0          generation-zeebe-1-1-0          Stable,Alpha
+Zeebe 1.2.0-alpha1   generation-zeebe-1-2-0-alpha1   Alpha
+
+REGION           ID                      ZONE
+Europe West 1D   region-europe-west-1d   europe-west1-d
+US East 1B       region-us-east-1b       us-east1-b
+`
+	b := &bytes.Buffer{}
+	if err := PrintCatalog(b, &p); err != nil {
+		t.Fatalf("PrintCatalog(...): %v", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("PrintCatalog(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TracerName is the name of the tracer spans of the provider are started with.
+const TracerName = "provider-camunda-cloud"
+
+type key struct {
+	kind string
+	name types.NamespacedName
+}
+
+// reconciles tracks the span of every in-flight reconcile. The managed
+// reconciler does not pass the context it is called with on to the external
+// client, so its methods look the span up here to nest under it.
+var reconciles sync.Map
+
+// A Reconciler starts a span for every reconcile of the wrapped reconciler.
+type Reconciler struct {
+	kind    string
+	wrapped reconcile.Reconciler
+	tracer  trace.Tracer
+}
+
+// NewReconciler wraps the supplied reconciler of the supplied kind so that
+// each of its reconciles is traced.
+func NewReconciler(kind string, r reconcile.Reconciler) *Reconciler {
+	return &Reconciler{kind: kind, wrapped: r, tracer: otel.Tracer(TracerName)}
+}
+
+// Reconcile the supplied request within a span.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := r.tracer.Start(ctx, "reconcile", trace.WithAttributes(
+		attribute.String("kind", r.kind),
+		attribute.String("name", req.Name),
+	))
+	defer span.End()
+
+	k := key{kind: r.kind, name: req.NamespacedName}
+	reconciles.Store(k, span)
+	defer reconciles.Delete(k)
+
+	result, err := r.wrapped.Reconcile(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// ContextWithReconcile returns a copy of the supplied context whose parent
+// span is that of the in-flight reconcile of the supplied resource, if any.
+func ContextWithReconcile(ctx context.Context, kind string, name types.NamespacedName) context.Context {
+	s, ok := reconciles.Load(key{kind: kind, name: name})
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, s.(trace.Span))
+}
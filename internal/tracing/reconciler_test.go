@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type reconcilerFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcilerFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func TestContextWithReconcile(t *testing.T) {
+	name := types.NamespacedName{Name: "cool"}
+
+	var reconcileSpan, externalSpan trace.SpanContext
+	wrapped := reconcilerFn(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		reconcileSpan = trace.SpanContextFromContext(ctx)
+
+		// The managed reconciler calls its external client with a context
+		// that is not derived from the one it was called with.
+		externalSpan = trace.SpanContextFromContext(ContextWithReconcile(context.Background(), "Cool", req.NamespacedName))
+		return reconcile.Result{}, nil
+	})
+
+	r := NewReconciler("Cool", wrapped)
+	r.tracer = sdktrace.NewTracerProvider().Tracer(TracerName)
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: name}); err != nil {
+		t.Fatalf("r.Reconcile(...): %v", err)
+	}
+
+	if !reconcileSpan.IsValid() {
+		t.Fatalf("r.Reconcile(...): no span was started")
+	}
+	if diff := cmp.Diff(reconcileSpan, externalSpan); diff != "" {
+		t.Errorf("ContextWithReconcile(...): -want, +got:\n%s\n", diff)
+	}
+	if sc := trace.SpanContextFromContext(ContextWithReconcile(context.Background(), "Cool", name)); sc.IsValid() {
+		t.Errorf("ContextWithReconcile(...): span of finished reconcile was returned")
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		kv     map[string]string
+		want   []string
+	}{
+		"Default": {
+			reason: "The service name should be reported if no attributes were supplied.",
+			want:   []string{"service.name=" + ServiceName},
+		},
+		"Sorted": {
+			reason: "Supplied attributes should be reported after the service name, sorted by key.",
+			kv:     map[string]string{"k8s.cluster.name": "prod", "deployment.environment": "eu"},
+			want:   []string{"service.name=" + ServiceName, "deployment.environment=eu", "k8s.cluster.name=prod"},
+		},
+		"ServiceName": {
+			reason: "A supplied service name should override the default.",
+			kv:     map[string]string{"service.name": "camunda"},
+			want:   []string{"service.name=camunda"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, a := range attributes(tc.kv) {
+				got = append(got, string(a.Key)+"="+a.Value.AsString())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nattributes(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing configures OpenTelemetry tracing for the provider.
+package tracing
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
+	"go.opentelemetry.io/otel/exporters/trace/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+// Supported exporters.
+const (
+	ExporterNone     = "none"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterJaeger   = "jaeger"
+)
+
+// Default endpoints of the supported exporters.
+const (
+	DefaultOTLPGRPCEndpoint = "localhost:4317"
+	DefaultOTLPHTTPEndpoint = "localhost:4318"
+	DefaultJaegerEndpoint   = "http://localhost:14268/api/traces"
+)
+
+// ServiceName is reported as the service.name resource attribute unless it is
+// overridden.
+const ServiceName = "provider-camunda-cloud"
+
+const (
+	errUnknownExporter = "unknown tracing exporter %q"
+	errSamplingRatio   = "tracing sampling ratio must be between 0 and 1, got %v"
+	errNewExporter     = "cannot create tracing exporter"
+)
+
+// Exporters lists the supported exporters.
+func Exporters() []string {
+	return []string{ExporterNone, ExporterOTLPGRPC, ExporterOTLPHTTP, ExporterJaeger}
+}
+
+// Options configure tracing.
+type Options struct {
+	// Exporter spans are sent with. One of Exporters().
+	Exporter string
+
+	// Endpoint spans are sent to. The exporter's default is used if empty.
+	Endpoint string
+
+	// Insecure disables TLS when talking to an OTLP endpoint.
+	Insecure bool
+
+	// SamplingRatio is the fraction of reconciles that are traced, between 0
+	// and 1. Spans whose parent was sampled are always sampled.
+	SamplingRatio float64
+
+	// Attributes are added to the resource spans are reported for.
+	Attributes map[string]string
+}
+
+// Setup installs a global tracer provider configured with the supplied
+// options. The returned function flushes and stops it, and must be called
+// before the provider exits.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	if o.SamplingRatio < 0 || o.SamplingRatio > 1 {
+		return nil, errors.Errorf(errSamplingRatio, o.SamplingRatio)
+	}
+
+	exp, err := newExporter(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	if exp == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SamplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(attributes(o.Attributes)...)),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, o Options) (exporttrace.SpanExporter, error) {
+	switch o.Exporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterOTLPGRPC:
+		opts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(endpoint(o.Endpoint, DefaultOTLPGRPCEndpoint))}
+		if o.Insecure {
+			opts = append(opts, otlpgrpc.WithInsecure())
+		}
+		exp, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(opts...))
+		return exp, errors.Wrap(err, errNewExporter)
+	case ExporterOTLPHTTP:
+		opts := []otlphttp.Option{otlphttp.WithEndpoint(endpoint(o.Endpoint, DefaultOTLPHTTPEndpoint))}
+		if o.Insecure {
+			opts = append(opts, otlphttp.WithInsecure())
+		}
+		exp, err := otlp.NewExporter(ctx, otlphttp.NewDriver(opts...))
+		return exp, errors.Wrap(err, errNewExporter)
+	case ExporterJaeger:
+		exp, err := jaeger.NewRawExporter(jaeger.WithCollectorEndpoint(endpoint(o.Endpoint, DefaultJaegerEndpoint)))
+		return exp, errors.Wrap(err, errNewExporter)
+	}
+	return nil, errors.Errorf(errUnknownExporter, o.Exporter)
+}
+
+func endpoint(e, def string) string {
+	if e == "" {
+		return def
+	}
+	return e
+}
+
+// attributes returns the resource attributes for the supplied key value pairs,
+// sorted by key, plus the service name unless it is among them.
+func attributes(kv map[string]string) []attribute.KeyValue {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(kv)+1)
+	if _, ok := kv[string(semconv.ServiceNameKey)]; !ok {
+		attrs = append(attrs, semconv.ServiceNameKey.String(ServiceName))
+	}
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, kv[k]))
+	}
+	return attrs
+}
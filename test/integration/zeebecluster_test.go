@@ -0,0 +1,652 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integration runs the provider's controllers against a real API
+// server, started by controller-runtime's envtest, and the fake Console API.
+// Run it with `make test-integration`; envtest finds the etcd and
+// kube-apiserver binaries in the directory set by KUBEBUILDER_ASSETS.
+package integration
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+
+	"github.com/salaboy/provider-camunda-cloud/apis"
+	ccv1alpha1 "github.com/salaboy/provider-camunda-cloud/apis/cc/v1alpha1"
+	ccv1beta1 "github.com/salaboy/provider-camunda-cloud/apis/cc/v1beta1"
+	"github.com/salaboy/provider-camunda-cloud/apis/v1alpha1"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller"
+	"github.com/salaboy/provider-camunda-cloud/internal/controller/options"
+	"github.com/salaboy/provider-camunda-cloud/internal/fake/console"
+)
+
+const (
+	namespace = "default"
+
+	// The provider observes up to date clusters far more often than it does
+	// by default, so that a new cluster is observed healthy soon after it is.
+	pollInterval = 5 * time.Second
+
+	// timeout bounds how long the tests wait for what they expect, which may
+	// take many reconciles while faults are injected.
+	timeout  = 3 * time.Minute
+	interval = 250 * time.Millisecond
+)
+
+// env is a running API server with the provider's CRDs installed and its
+// controllers started, backed by a fake Console API.
+type env struct {
+	kube    client.Client
+	console *console.Console
+	server  *httptest.Server
+	webhook envtest.WebhookInstallOptions
+}
+
+func start(t *testing.T, o ...console.Option) *env {
+	t.Helper()
+
+	con := console.New(o...)
+	srv := httptest.NewServer(con)
+	t.Cleanup(srv.Close)
+
+	// The manager shuts down an event broadcaster of its own while reconciles
+	// may still be recording events, so supply one that outlives it. It is
+	// never shut down either: a reconcile of a stopped manager may record an
+	// event while a later test runs.
+	eb := record.NewBroadcaster()
+
+	te := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "package", "crds")},
+		ErrorIfCRDPathMissing: true,
+		// Authorize with RBAC, so that ZeebeClusterClaims may only use the
+		// ProviderConfigs their namespace is granted. The clients of the
+		// tests are not authorized, since they use the insecure port.
+		KubeAPIServerFlags: append([]string{"--authorization-mode=RBAC"}, envtest.DefaultKubeAPIServerFlags...),
+		// envtest points the webhook configuration at a local webhook
+		// server with a certificate of its own.
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "cluster", "webhook")},
+		},
+	}
+	cfg, err := te.Start()
+	if err != nil {
+		t.Fatalf("cannot start API server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := te.Stop(); err != nil {
+			t.Errorf("cannot stop API server: %v", err)
+		}
+	})
+
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("cannot add Kubernetes APIs to scheme: %v", err)
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatalf("cannot add provider APIs to scheme: %v", err)
+	}
+
+	wo := te.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             s,
+		MetricsBindAddress: "0",
+		EventBroadcaster:   eb,
+		Host:               wo.LocalServingHost,
+		Port:               wo.LocalServingPort,
+		CertDir:            wo.LocalServingCertDir,
+	})
+	if err != nil {
+		t.Fatalf("cannot create controller manager: %v", err)
+	}
+	co := options.Options{
+		GlobalRateLimiter:       ratelimiter.NewDefaultProviderRateLimiter(ratelimiter.DefaultProviderRPS),
+		PollInterval:            pollInterval,
+		MaxConcurrentReconciles: options.DefaultMaxConcurrentReconciles,
+	}
+	if err := controller.Setup(mgr, logging.NewNopLogger(), co); err != nil {
+		t.Fatalf("cannot set up controllers: %v", err)
+	}
+	if err := controller.SetupWebhooks(mgr, logging.NewNopLogger()); err != nil {
+		t.Fatalf("cannot set up webhooks: %v", err)
+	}
+
+	// Read what the controllers wrote straight from the API server rather
+	// than through the manager's cache, which may not have caught up yet.
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		t.Fatalf("cannot create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("cannot start controller manager: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+
+	// The webhook configuration ignores a webhook server that is not up yet,
+	// which would let invalid ZeebeClusters in.
+	ca := x509.NewCertPool()
+	ca.AppendCertsFromPEM(wo.LocalServingCAData)
+	addr := net.JoinHostPort(wo.LocalServingHost, strconv.Itoa(wo.LocalServingPort))
+	eventually(t, "the webhook server should be serving", func() (bool, error) {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: ca})
+		if err != nil {
+			return false, nil
+		}
+		return true, conn.Close()
+	})
+
+	return &env{kube: kube, console: con, server: srv, webhook: wo}
+}
+
+// serveV1beta1 applies the patch that serves v1beta1 ZeebeClusters to their
+// CRD, pointing its conversion webhook at the local webhook server rather
+// than the provider's Service.
+func (e *env) serveV1beta1(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	y, err := ioutil.ReadFile(filepath.Join("..", "..", "cluster", "conversion", "zeebeclusters.yaml"))
+	if err != nil {
+		t.Fatalf("cannot read conversion patch: %v", err)
+	}
+	p, err := yaml.YAMLToJSON(y)
+	if err != nil {
+		t.Fatalf("cannot parse conversion patch: %v", err)
+	}
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	crd.SetName("zeebeclusters.cc.camunda.crossplane.io")
+	if err := e.kube.Patch(ctx, crd, client.RawPatch(types.JSONPatchType, p)); err != nil {
+		t.Fatalf("cannot apply conversion patch: %v", err)
+	}
+
+	url := fmt.Sprintf("https://%s/convert", net.JoinHostPort(e.webhook.LocalServingHost, strconv.Itoa(e.webhook.LocalServingPort)))
+	local := fmt.Sprintf(`{"spec":{"conversion":{"webhook":{"clientConfig":{"service":null,"url":%q,"caBundle":%q}}}}}`,
+		url, base64.StdEncoding.EncodeToString(e.webhook.LocalServingCAData))
+	if err := e.kube.Patch(ctx, crd, client.RawPatch(types.MergePatchType, []byte(local))); err != nil {
+		t.Fatalf("cannot point conversion webhook at local webhook server: %v", err)
+	}
+
+	eventually(t, "v1beta1 ZeebeClusters should be served", func() (bool, error) {
+		return e.kube.List(ctx, &ccv1beta1.ZeebeClusterList{}) == nil, nil
+	})
+}
+
+// providerConfig creates a ProviderConfig that logs in to the fake Console API
+// with credentials read from a Secret.
+func (e *env) providerConfig(t *testing.T, ctx context.Context, name string) {
+	t.Helper()
+
+	creds := fmt.Sprintf(`{"ccClientId": %q, "ccSecretId": %q}`, console.DefaultClientID, console.DefaultClientSecret)
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name + "-credentials"},
+		StringData: map[string]string{"credentials": creds},
+	}
+	if err := e.kube.Create(ctx, s); err != nil {
+		t.Fatalf("cannot create credentials secret: %v", err)
+	}
+
+	pc := &v1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.ProviderConfigSpec{
+			Credentials: v1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: namespace, Name: s.GetName()},
+						Key:             "credentials",
+					},
+				},
+			},
+			Endpoints: &v1alpha1.Endpoints{
+				Login: e.server.URL + console.LoginPath,
+				API:   e.server.URL,
+			},
+		},
+	}
+	if err := e.kube.Create(ctx, pc); err != nil {
+		t.Fatalf("cannot create ProviderConfig: %v", err)
+	}
+}
+
+// eventually polls fn until it returns true, failing the test with what
+// was expected if it does not do so within the timeout.
+func eventually(t *testing.T, what string, fn func() (bool, error)) {
+	t.Helper()
+	if err := wait.PollImmediate(interval, timeout, fn); err != nil {
+		t.Fatalf("%s: %v", what, err)
+	}
+}
+
+// zeebeCluster returns a ZeebeCluster that uses the named ProviderConfig and
+// writes its connection secret to a Secret named after it.
+func zeebeCluster(name, providerConfig string) *ccv1alpha1.ZeebeCluster {
+	return &ccv1alpha1.ZeebeCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: ccv1alpha1.ZeebeClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference:          &xpv1.Reference{Name: providerConfig},
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: namespace, Name: name + "-zeebe"},
+			},
+			ForProvider: ccv1alpha1.ZeebeClusterParameters{
+				PlanName:    "Development",
+				ChannelName: "Stable",
+				Region:      "Europe West 1D",
+			},
+		},
+	}
+}
+
+func TestZeebeClusterLifecycle(t *testing.T) {
+	e := start(t, console.WithCreatingFor(2*time.Second), console.WithDeletingFor(2*time.Second))
+	ctx := context.Background()
+
+	e.providerConfig(t, ctx, "fake-console")
+
+	zb := zeebeCluster("cool", "fake-console")
+	if err := e.kube.Create(ctx, zb); err != nil {
+		t.Fatalf("cannot create ZeebeCluster: %v", err)
+	}
+	nn := types.NamespacedName{Name: zb.GetName()}
+
+	eventually(t, "the ZeebeCluster should become available once its cluster is healthy", func() (bool, error) {
+		if err := e.kube.Get(ctx, nn, zb); err != nil {
+			return false, err
+		}
+		return zb.GetCondition(xpv1.TypeReady).Equal(xpv1.Available()), nil
+	})
+
+	cl := e.console.Clusters()
+	if len(cl) != 1 {
+		t.Fatalf("exactly one cluster should have been created, got %d", len(cl))
+	}
+	if zb.Status.AtProvider.ClusterId != cl[0].ID {
+		t.Errorf("status.atProvider.clusterId: want %q, got %q", cl[0].ID, zb.Status.AtProvider.ClusterId)
+	}
+	if zb.Status.AtProvider.ClusterStatus.Ready != console.StatusHealthy {
+		t.Errorf("status.atProvider.ready: want %q, got %q", console.StatusHealthy, zb.Status.AtProvider.ClusterStatus.Ready)
+	}
+
+	s := &corev1.Secret{}
+	eventually(t, "the connection secret should be written", func() (bool, error) {
+		err := e.kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "cool-zeebe"}, s)
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil && len(s.Data["zeebeAddress"]) > 0, err
+	})
+	if got := string(s.Data["clusterId"]); got != cl[0].ID {
+		t.Errorf("connection secret clusterId: want %q, got %q", cl[0].ID, got)
+	}
+
+	if err := e.kube.Delete(ctx, zb); err != nil {
+		t.Fatalf("cannot delete ZeebeCluster: %v", err)
+	}
+	eventually(t, "the ZeebeCluster should be gone once its finalizer is removed", func() (bool, error) {
+		err := e.kube.Get(ctx, nn, &ccv1alpha1.ZeebeCluster{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if cl := e.console.Clusters(); len(cl) != 0 {
+		t.Errorf("the cluster should have been deleted, got %+v", cl)
+	}
+}
+
+func TestZeebeClusterDuringOutage(t *testing.T) {
+	faults := console.Faults{
+		Latency:           50 * time.Millisecond,
+		ServerErrors:      3,
+		Throttled:         1,
+		RetryAfter:        time.Second,
+		ExpireTokensAfter: 4,
+		PartialCreates:    1,
+	}
+	e := start(t, console.WithCreatingFor(2*time.Second), console.WithDeletingFor(2*time.Second), console.WithFaults(faults))
+	ctx := context.Background()
+
+	e.providerConfig(t, ctx, "fake-console")
+
+	zb := zeebeCluster("cool", "fake-console")
+	if err := e.kube.Create(ctx, zb); err != nil {
+		t.Fatalf("cannot create ZeebeCluster: %v", err)
+	}
+	nn := types.NamespacedName{Name: zb.GetName()}
+
+	eventually(t, "the ZeebeCluster should become available despite the outage", func() (bool, error) {
+		if cl := e.console.Clusters(); len(cl) > 1 {
+			return false, fmt.Errorf("want at most one cluster, got %d: %+v", len(cl), cl)
+		}
+		if err := e.kube.Get(ctx, nn, zb); err != nil {
+			return false, err
+		}
+		return zb.GetCondition(xpv1.TypeReady).Equal(xpv1.Available()), nil
+	})
+	if f := e.console.Faults(); f.ServerErrors+f.Throttled+f.ExpireTokensAfter+f.PartialCreates != 0 {
+		t.Errorf("not all faults were injected while creating the cluster: %+v", f)
+	}
+	if cl := e.console.Clusters(); len(cl) != 1 || cl[0].ID != zb.Status.AtProvider.ClusterId {
+		t.Fatalf("want exactly the cluster %q, got %+v", zb.Status.AtProvider.ClusterId, cl)
+	}
+
+	e.console.Inject(faults)
+	if err := e.kube.Delete(ctx, zb); err != nil {
+		t.Fatalf("cannot delete ZeebeCluster: %v", err)
+	}
+	eventually(t, "the ZeebeCluster should be gone despite the outage", func() (bool, error) {
+		err := e.kube.Get(ctx, nn, &ccv1alpha1.ZeebeCluster{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if cl := e.console.Clusters(); len(cl) != 0 {
+		t.Errorf("want no orphaned clusters once the ZeebeCluster is deleted, got %+v", cl)
+	}
+}
+
+func TestZeebeClusterValidation(t *testing.T) {
+	e := start(t)
+	ctx := context.Background()
+
+	e.providerConfig(t, ctx, "fake-console")
+
+	zb := zeebeCluster("cool", "fake-console")
+	zb.Spec.ForProvider.ChannelName = "Nightly"
+	err := e.kube.Create(ctx, zb)
+	if err == nil || !strings.Contains(err.Error(), `spec.forProvider.channelName: Unsupported value: "Nightly"`) {
+		t.Fatalf("a ZeebeCluster with a channel the Console does not offer should be denied, got %v", err)
+	}
+
+	zb = zeebeCluster("cool", "fake-console")
+	if err := e.kube.Create(ctx, zb); err != nil {
+		t.Fatalf("cannot create ZeebeCluster: %v", err)
+	}
+	// The controller updates the ZeebeCluster too, so retry on conflicts.
+	eventually(t, "the ZeebeCluster should be updated", func() (bool, error) {
+		if err := e.kube.Get(ctx, types.NamespacedName{Name: zb.GetName()}, zb); err != nil {
+			return false, err
+		}
+		zb.Spec.ForProvider.Region = "US East 1B"
+		err = e.kube.Update(ctx, zb)
+		return !kerrors.IsConflict(err), nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "spec.forProvider.region") {
+		t.Errorf("changing the region of a ZeebeCluster should be denied, got %v", err)
+	}
+}
+
+func TestZeebeClusterConversion(t *testing.T) {
+	e := start(t, console.WithCreatingFor(2*time.Second), console.WithDeletingFor(2*time.Second))
+	ctx := context.Background()
+
+	e.providerConfig(t, ctx, "fake-console")
+
+	// The packaged CRD neither serves v1beta1 nor calls a conversion
+	// webhook, which does not exist in a default install.
+	if err := e.kube.List(ctx, &ccv1beta1.ZeebeClusterList{}); !meta.IsNoMatchError(err) {
+		t.Fatalf("v1beta1 ZeebeClusters should not be served by the packaged CRD, got %v", err)
+	}
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	if err := e.kube.Get(ctx, types.NamespacedName{Name: "zeebeclusters.cc.camunda.crossplane.io"}, crd); err != nil {
+		t.Fatalf("cannot get ZeebeCluster CRD: %v", err)
+	}
+	if s, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy"); s != "None" {
+		t.Errorf("the packaged ZeebeCluster CRD should not convert with a webhook, got strategy %q", s)
+	}
+
+	e.serveV1beta1(t, ctx)
+
+	// A ZeebeCluster created by names reads as v1beta1 with the IDs of the
+	// cluster it was observed with.
+	alpha := zeebeCluster("cool", "fake-console")
+	if err := e.kube.Create(ctx, alpha); err != nil {
+		t.Fatalf("cannot create v1alpha1 ZeebeCluster: %v", err)
+	}
+	beta := &ccv1beta1.ZeebeCluster{}
+	eventually(t, "the v1beta1 ZeebeCluster should identify its plan, channel and region by ID", func() (bool, error) {
+		if err := e.kube.Get(ctx, types.NamespacedName{Name: alpha.GetName()}, beta); err != nil {
+			return false, err
+		}
+		return beta.Spec.ForProvider.PlanID != "", nil
+	})
+	want := ccv1beta1.ZeebeClusterParameters{PlanID: "plan-development", ChannelID: "channel-stable", GenerationID: "generation-zeebe-1-0-0", RegionID: "region-europe-west-1d"}
+	if beta.Spec.ForProvider != want {
+		t.Errorf("v1beta1 spec.forProvider: want %+v, got %+v", want, beta.Spec.ForProvider)
+	}
+	if got := beta.Status.AtProvider.Plan.Name; got != "Development" {
+		t.Errorf("v1beta1 status.atProvider.plan.name: want %q, got %q", "Development", got)
+	}
+
+	// A v1beta1 update round trips to storage without losing the names the
+	// ZeebeCluster was created with.
+	eventually(t, "the v1beta1 ZeebeCluster should be updated", func() (bool, error) {
+		if err := e.kube.Get(ctx, types.NamespacedName{Name: alpha.GetName()}, beta); err != nil {
+			return false, err
+		}
+		beta.SetLabels(map[string]string{"cool": "label"})
+		err := e.kube.Update(ctx, beta)
+		if kerrors.IsConflict(err) {
+			return false, nil
+		}
+		return true, err
+	})
+	if err := e.kube.Get(ctx, types.NamespacedName{Name: alpha.GetName()}, alpha); err != nil {
+		t.Fatalf("cannot get v1alpha1 ZeebeCluster: %v", err)
+	}
+	if p := alpha.Spec.ForProvider; p.PlanName != "Development" || p.ChannelName != "Stable" || p.Region != "Europe West 1D" {
+		t.Errorf("v1alpha1 spec.forProvider should keep its names, got %+v", p)
+	}
+	if _, ok := alpha.GetAnnotations()[ccv1alpha1.AnnotationKeyNames]; ok {
+		t.Errorf("v1alpha1 ZeebeCluster should not be annotated with its names, got %v", alpha.GetAnnotations())
+	}
+
+	// A ZeebeCluster created by IDs gets a cluster with those IDs.
+	cooler := &ccv1beta1.ZeebeCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cooler"},
+		Spec: ccv1beta1.ZeebeClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "fake-console"}},
+			ForProvider: ccv1beta1.ZeebeClusterParameters{
+				PlanID:    "plan-production-s",
+				ChannelID: "channel-alpha",
+				RegionID:  "region-us-east-1b",
+			},
+		},
+	}
+	if err := e.kube.Create(ctx, cooler); err != nil {
+		t.Fatalf("cannot create v1beta1 ZeebeCluster: %v", err)
+	}
+	eventually(t, "the v1beta1 ZeebeCluster should become available", func() (bool, error) {
+		if err := e.kube.Get(ctx, types.NamespacedName{Name: cooler.GetName()}, cooler); err != nil {
+			return false, err
+		}
+		return cooler.GetCondition(xpv1.TypeReady).Equal(xpv1.Available()), nil
+	})
+	id := cooler.Status.AtProvider.ClusterID
+	for _, cl := range e.console.Clusters() {
+		if cl.ID != id {
+			continue
+		}
+		if cl.ClusterPlantType.Id != "plan-production-s" || cl.Channel.Id != "channel-alpha" || cl.K8sContext.ID != "region-us-east-1b" {
+			t.Errorf("cluster %q should have the plan, channel and region of the v1beta1 ZeebeCluster, got %+v", id, cl)
+		}
+	}
+	if got := cooler.Status.AtProvider.Generation.ID; got != "generation-zeebe-1-2-0-alpha1" {
+		t.Errorf("v1beta1 status.atProvider.generation.id: want the channel's default %q, got %q", "generation-zeebe-1-2-0-alpha1", got)
+	}
+
+	// A ZeebeCluster created as v1beta1 reads as v1alpha1 with the same IDs
+	// and the names of the cluster it was observed with.
+	coolerAlpha := &ccv1alpha1.ZeebeCluster{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Name: cooler.GetName()}, coolerAlpha); err != nil {
+		t.Fatalf("cannot get v1alpha1 ZeebeCluster: %v", err)
+	}
+	if p := coolerAlpha.Spec.ForProvider; p.PlanId != "plan-production-s" || p.ChannelId != "channel-alpha" || p.RegionId != "region-us-east-1b" {
+		t.Errorf("v1alpha1 spec.forProvider should keep the v1beta1 IDs, got %+v", p)
+	}
+	if got := coolerAlpha.Status.AtProvider.ClusterId; got != id {
+		t.Errorf("v1alpha1 status.atProvider.clusterId: want %q, got %q", id, got)
+	}
+
+	for _, zb := range []client.Object{alpha, cooler} {
+		if err := e.kube.Delete(ctx, zb); err != nil {
+			t.Fatalf("cannot delete ZeebeCluster: %v", err)
+		}
+	}
+	eventually(t, "the clusters should be deleted", func() (bool, error) {
+		return len(e.console.Clusters()) == 0, nil
+	})
+}
+
+func TestZeebeClusterClaim(t *testing.T) {
+	e := start(t, console.WithCreatingFor(2*time.Second), console.WithDeletingFor(2*time.Second))
+	ctx := context.Background()
+
+	e.providerConfig(t, ctx, "team-a")
+	if err := e.kube.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}); err != nil {
+		t.Fatalf("cannot create namespace: %v", err)
+	}
+
+	cm := &ccv1alpha1.ZeebeClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "orders"},
+		Spec: ccv1alpha1.ZeebeClusterClaimSpec{
+			ProviderConfigReference:          &xpv1.Reference{Name: "team-a"},
+			WriteConnectionSecretToReference: &xpv1.LocalSecretReference{Name: "orders-zeebe"},
+			ForProvider:                      zeebeCluster("orders", "team-a").Spec.ForProvider,
+		},
+	}
+	if err := e.kube.Create(ctx, cm); err != nil {
+		t.Fatalf("cannot create ZeebeClusterClaim: %v", err)
+	}
+	nn := types.NamespacedName{Namespace: cm.GetNamespace(), Name: cm.GetName()}
+
+	eventually(t, "the claim should not be bound before its namespace may use its ProviderConfig", func() (bool, error) {
+		if err := e.kube.Get(ctx, nn, cm); err != nil {
+			return false, err
+		}
+		c := cm.GetCondition(xpv1.TypeSynced)
+		return c.Reason == xpv1.ReasonReconcileError && strings.Contains(c.Message, `may not use ProviderConfig "team-a"`), nil
+	})
+	if cl := e.console.Clusters(); len(cl) != 0 {
+		t.Fatalf("no cluster should have been created, got %+v", cl)
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "camunda-providerconfig:team-a"},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{v1alpha1.Group},
+			Resources:     []string{"providerconfigs"},
+			ResourceNames: []string{"team-a"},
+			Verbs:         []string{"use"},
+		}},
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: role.GetName()},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role.GetName()},
+		Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:team-a"}},
+	}
+	for _, o := range []client.Object{role, binding} {
+		if err := e.kube.Create(ctx, o); err != nil {
+			t.Fatalf("cannot create RBAC: %v", err)
+		}
+	}
+	// The claim is only checked again after a while, so nudge it.
+	eventually(t, "the claim should be nudged", func() (bool, error) {
+		if err := e.kube.Get(ctx, nn, cm); err != nil {
+			return false, err
+		}
+		cm.SetLabels(map[string]string{"nudge": "true"})
+		err := e.kube.Update(ctx, cm)
+		if kerrors.IsConflict(err) {
+			return false, nil
+		}
+		return true, err
+	})
+
+	eventually(t, "the claim should become available once its cluster is healthy", func() (bool, error) {
+		if err := e.kube.Get(ctx, nn, cm); err != nil {
+			return false, err
+		}
+		return cm.GetCondition(xpv1.TypeReady).Equal(xpv1.Available()), nil
+	})
+	if cm.Status.ClusterReference == nil || cm.Status.ClusterReference.Name != "team-a-orders" {
+		t.Errorf("status.clusterRef: want %q, got %+v", "team-a-orders", cm.Status.ClusterReference)
+	}
+
+	s := &corev1.Secret{}
+	eventually(t, "the connection secret should be written to the claim's namespace", func() (bool, error) {
+		err := e.kube.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "orders-zeebe"}, s)
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil && len(s.Data["zeebeAddress"]) > 0, err
+	})
+	if got := string(s.Data["clusterId"]); got != cm.Status.AtProvider.ClusterId {
+		t.Errorf("connection secret clusterId: want %q, got %q", cm.Status.AtProvider.ClusterId, got)
+	}
+
+	if err := e.kube.Delete(ctx, cm); err != nil {
+		t.Fatalf("cannot delete ZeebeClusterClaim: %v", err)
+	}
+	eventually(t, "the claimed cluster should be deleted with the claim", func() (bool, error) {
+		err := e.kube.Get(ctx, types.NamespacedName{Name: "team-a-orders"}, &ccv1alpha1.ZeebeCluster{})
+		return kerrors.IsNotFound(err) && len(e.console.Clusters()) == 0, nil
+	})
+}